@@ -0,0 +1,284 @@
+package zlogger
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+type overflowKind uint8
+
+const (
+	overflowBlock overflowKind = iota
+	overflowDropNewest
+	overflowDropOldest
+	overflowDropBelow
+)
+
+// OverflowPolicy 決定非同步佇列已滿時如何處理新日誌。
+//
+// 零值等同 OverflowBlock。
+type OverflowPolicy struct {
+	kind     overflowKind
+	minLevel zapcore.Level
+}
+
+// OverflowBlock 在佇列已滿時讓呼叫端等待空位，不丟棄日誌。
+func OverflowBlock() OverflowPolicy {
+	return OverflowPolicy{kind: overflowBlock}
+}
+
+// OverflowDropNewest 在佇列已滿時丟棄新日誌，呼叫端不等待。
+func OverflowDropNewest() OverflowPolicy {
+	return OverflowPolicy{kind: overflowDropNewest}
+}
+
+// OverflowDropOldest 在佇列已滿時丟棄最舊的待寫日誌，再排入新日誌。
+func OverflowDropOldest() OverflowPolicy {
+	return OverflowPolicy{kind: overflowDropOldest}
+}
+
+// OverflowDropBelow 在佇列已滿時丟棄低於 level 的新日誌；level 以上的日誌等待空位。
+func OverflowDropBelow(level zapcore.Level) OverflowPolicy {
+	return OverflowPolicy{kind: overflowDropBelow, minLevel: level}
+}
+
+func (p OverflowPolicy) validate() error {
+	switch p.kind {
+	case overflowBlock, overflowDropNewest, overflowDropOldest:
+		return nil
+	case overflowDropBelow:
+		if p.minLevel < zapcore.DebugLevel || p.minLevel > zapcore.FatalLevel {
+			return fmt.Errorf("%w: 佇列滿載時保留的級別 %s 無效", ErrInvalidConfig, p.minLevel)
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: 未知的佇列滿載策略 %d", ErrInvalidConfig, p.kind)
+	}
+}
+
+// WithAsync 讓 Instance 將日誌排入容量為 queueSize 的佇列，由背景 goroutine 寫入所有輸出。
+//
+// 呼叫端只在佇列已滿且 policy 為 OverflowBlock 或 OverflowDropBelow 時等待；被丟棄的筆數
+// 可由 Instance.Stats 取得。DPANIC 以上的日誌會先等待佇列寫完再同步寫入，避免 panic 或
+// 結束程序前遺失。Sync 等待佇列清空；Close 先寫完已排入的日誌再關閉檔案。
+// Field 於背景 goroutine 編碼，記錄後不得再修改其參照的資料。只適用於 NewWithOptions
+// 與 ConfigureWithOptions；ErrorHandler 不應透過同一個 Instance 記錄日誌。背景寫出失敗時，
+// 設定 ErrorHandler 以 InternalEventWrite 回報，否則寫入 stderr。
+func WithAsync(queueSize int, policy OverflowPolicy) FileOutputOption {
+	return fileOutputOptionFunc(func(settings *fileOutputSettings) error {
+		if queueSize <= 0 {
+			return fmt.Errorf("%w: 非同步佇列容量必須大於 0", ErrInvalidConfig)
+		}
+		if err := policy.validate(); err != nil {
+			return err
+		}
+		settings.asyncQueueSize = queueSize
+		settings.overflow = policy
+		return nil
+	})
+}
+
+// asyncEntry 保存待寫入的日誌與接收它的 core。
+type asyncEntry struct {
+	core   zapcore.Core
+	entry  zapcore.Entry
+	fields []zapcore.Field
+}
+
+// asyncQueue 是固定容量的環狀佇列，由單一 goroutine 依序寫出。
+//
+// close 會拒絕新日誌，並在寫完所有已排入的日誌後才回傳。dropped 可由多個佇列共用，
+// 讓重新載入設定後的統計持續累計。寫出失敗時 zap 將錯誤寫入 errorOutput。
+type asyncQueue struct {
+	policy      OverflowPolicy
+	dropped     *atomic.Uint64
+	errorOutput zapcore.WriteSyncer
+
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	idle     *sync.Cond
+	entries  []asyncEntry
+	head     int
+	count    int
+	busy     bool
+	closed   bool
+	done     chan struct{}
+}
+
+func newAsyncQueue(
+	size int,
+	policy OverflowPolicy,
+	dropped *atomic.Uint64,
+	errorOutput zapcore.WriteSyncer,
+) *asyncQueue {
+	queue := &asyncQueue{
+		policy:      policy,
+		dropped:     dropped,
+		errorOutput: errorOutput,
+		entries:     make([]asyncEntry, size),
+		done:        make(chan struct{}),
+	}
+	queue.notEmpty = sync.NewCond(&queue.mu)
+	queue.notFull = sync.NewCond(&queue.mu)
+	queue.idle = sync.NewCond(&queue.mu)
+	go queue.run()
+	return queue
+}
+
+// push 依 policy 排入日誌；佇列已關閉時回傳 os.ErrClosed。
+func (q *asyncQueue) push(entry asyncEntry) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for {
+		if q.closed {
+			return fmt.Errorf("非同步日誌佇列已關閉：%w", os.ErrClosed)
+		}
+		if q.count < len(q.entries) {
+			q.entries[(q.head+q.count)%len(q.entries)] = entry
+			q.count++
+			q.notEmpty.Signal()
+			return nil
+		}
+
+		switch q.policy.kind {
+		case overflowDropNewest:
+			q.dropped.Add(1)
+			return nil
+		case overflowDropOldest:
+			q.pop()
+			q.dropped.Add(1)
+			continue
+		case overflowDropBelow:
+			if entry.entry.Level < q.policy.minLevel {
+				q.dropped.Add(1)
+				return nil
+			}
+		}
+		q.notFull.Wait()
+	}
+}
+
+// pop 取出最舊的日誌；呼叫端必須持有 mutex 且佇列非空。
+func (q *asyncQueue) pop() asyncEntry {
+	entry := q.entries[q.head]
+	q.entries[q.head] = asyncEntry{}
+	q.head = (q.head + 1) % len(q.entries)
+	q.count--
+	return entry
+}
+
+func (q *asyncQueue) run() {
+	defer close(q.done)
+
+	for {
+		q.mu.Lock()
+		for q.count == 0 && !q.closed {
+			q.notEmpty.Wait()
+		}
+		if q.count == 0 {
+			q.idle.Broadcast()
+			q.mu.Unlock()
+			return
+		}
+		entry := q.pop()
+		q.busy = true
+		q.notFull.Signal()
+		q.mu.Unlock()
+
+		q.write(entry)
+
+		q.mu.Lock()
+		q.busy = false
+		if q.count == 0 {
+			q.idle.Broadcast()
+		}
+		q.mu.Unlock()
+	}
+}
+
+// write 以 core 的 Check 重新判斷級別、路由與磁碟空間後寫出。
+//
+// 背景寫出沒有呼叫端可接收錯誤，因此將 errorOutput 交給 CheckedEntry，由 zap 回報
+// 寫入失敗；設定 ErrorHandler 時即為 InternalEventWrite。
+func (q *asyncQueue) write(entry asyncEntry) {
+	if checked := entry.core.Check(entry.entry, nil); checked != nil {
+		checked.ErrorOutput = q.errorOutput
+		checked.Write(entry.fields...)
+	}
+}
+
+// drain 等待已排入的日誌全部寫出。
+func (q *asyncQueue) drain() {
+	q.mu.Lock()
+	for q.count > 0 || q.busy {
+		q.idle.Wait()
+	}
+	q.mu.Unlock()
+}
+
+// close 停止接受日誌並等待佇列寫完，可重複呼叫；nil queue 直接回傳。
+func (q *asyncQueue) close() {
+	if q == nil {
+		return
+	}
+
+	q.mu.Lock()
+	q.closed = true
+	q.notEmpty.Broadcast()
+	q.notFull.Broadcast()
+	q.mu.Unlock()
+	<-q.done
+}
+
+// asyncCore 將通過級別檢查的日誌排入 asyncQueue，由背景 goroutine 交給內層 core。
+type asyncCore struct {
+	core  zapcore.Core
+	queue *asyncQueue
+}
+
+func newAsyncCore(core zapcore.Core, queue *asyncQueue) zapcore.Core {
+	return &asyncCore{core: core, queue: queue}
+}
+
+func (c *asyncCore) Enabled(level zapcore.Level) bool {
+	return c.core.Enabled(level)
+}
+
+// Level 回傳內層 core 的最低啟用級別，供 zapcore.LevelOf 使用。
+func (c *asyncCore) Level() zapcore.Level {
+	return zapcore.LevelOf(c.core)
+}
+
+func (c *asyncCore) With(fields []zapcore.Field) zapcore.Core {
+	return &asyncCore{core: c.core.With(fields), queue: c.queue}
+}
+
+func (c *asyncCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *asyncCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	queued := asyncEntry{core: c.core, entry: entry, fields: slices.Clone(fields)}
+	if entry.Level > zapcore.ErrorLevel {
+		c.queue.drain()
+		c.queue.write(queued)
+		return nil
+	}
+	return c.queue.push(queued)
+}
+
+// Sync 等待佇列清空後同步內層 core。
+func (c *asyncCore) Sync() error {
+	c.queue.drain()
+	return c.core.Sync()
+}
@@ -0,0 +1,229 @@
+package zlogger
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// gatedSink 記錄每筆日誌；每次寫入都等待 gate 關閉，並在第一次寫入時通知 started。
+type gatedSink struct {
+	started chan struct{}
+	gate    chan struct{}
+
+	mu    sync.Mutex
+	lines []string
+}
+
+func newGatedSink() *gatedSink {
+	return &gatedSink{started: make(chan struct{}, 1), gate: make(chan struct{})}
+}
+
+func (s *gatedSink) Write(p []byte) (int, error) {
+	select {
+	case s.started <- struct{}{}:
+	default:
+	}
+	<-s.gate
+	s.mu.Lock()
+	s.lines = append(s.lines, strings.TrimSpace(string(p)))
+	s.mu.Unlock()
+	return len(p), nil
+}
+
+func (s *gatedSink) Sync() error {
+	return nil
+}
+
+func (s *gatedSink) snapshot() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.lines...)
+}
+
+func newAsyncTestLogger(sink *gatedSink, size int, policy OverflowPolicy) (*zap.Logger, *asyncQueue) {
+	encoder := zapcore.NewConsoleEncoder(zapcore.EncoderConfig{MessageKey: "msg"})
+	queue := newAsyncQueue(size, policy, new(atomic.Uint64), zapcore.AddSync(io.Discard))
+	core := newAsyncCore(zapcore.NewCore(encoder, sink, zapcore.DebugLevel), queue)
+	return zap.New(core), queue
+}
+
+func TestWithAsyncRejectsInvalidSettings(t *testing.T) {
+	tests := []struct {
+		name   string
+		option FileOutputOption
+	}{
+		{name: "容量為 0", option: WithAsync(0, OverflowBlock())},
+		{name: "保留級別無效", option: WithAsync(8, OverflowDropBelow(zapcore.FatalLevel+1))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instance, err := NewWithOptions(fileOutputTestConfig(t.TempDir(), "app.log"), tt.option)
+			if instance != nil {
+				_ = instance.Close()
+				t.Fatal("無效設定不應回傳 Instance")
+			}
+			if !errors.Is(err, ErrInvalidConfig) {
+				t.Fatalf("錯誤 = %v，預期 ErrInvalidConfig", err)
+			}
+		})
+	}
+}
+
+func TestAsyncQueueOverflowPolicies(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      OverflowPolicy
+		want        []string
+		wantDropped uint64
+	}{
+		{name: "block", policy: OverflowBlock(), want: []string{"1", "2", "3", "4", "5"}},
+		{name: "drop newest", policy: OverflowDropNewest(), want: []string{"1", "2", "3"}, wantDropped: 2},
+		{name: "drop oldest", policy: OverflowDropOldest(), want: []string{"1", "4", "5"}, wantDropped: 2},
+		{
+			name:        "drop below",
+			policy:      OverflowDropBelow(zapcore.WarnLevel),
+			want:        []string{"1", "2", "3", "5"},
+			wantDropped: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := newGatedSink()
+			logger, queue := newAsyncTestLogger(sink, 2, tt.policy)
+			defer queue.close()
+
+			logger.Info("1")
+			select {
+			case <-sink.started:
+			case <-time.After(time.Second):
+				t.Fatal("等待背景寫入開始逾時")
+			}
+			logger.Info("2")
+			logger.Info("3")
+
+			// 佇列已滿；只有 block 會讓 INFO 等待，drop below 的 WARN 同樣等待空位。
+			blocking := tt.policy.kind == overflowBlock
+			if !blocking {
+				logger.Info("4")
+			}
+			overflowed := make(chan struct{})
+			go func() {
+				defer close(overflowed)
+				if blocking {
+					logger.Info("4")
+				}
+				logger.Warn("5")
+			}()
+			if tt.policy.kind != overflowBlock && tt.policy.kind != overflowDropBelow {
+				<-overflowed
+			}
+			close(sink.gate)
+			<-overflowed
+			if err := logger.Sync(); err != nil {
+				t.Fatalf("同步失敗：%v", err)
+			}
+
+			if got := sink.snapshot(); strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Fatalf("寫出 = %v，預期 %v", got, tt.want)
+			}
+			if dropped := queue.dropped.Load(); dropped != tt.wantDropped {
+				t.Fatalf("丟棄 %d 筆，預期 %d", dropped, tt.wantDropped)
+			}
+		})
+	}
+}
+
+func TestAsyncCoreWritesDPanicSynchronously(t *testing.T) {
+	sink := newGatedSink()
+	close(sink.gate)
+	logger, queue := newAsyncTestLogger(sink, 8, OverflowBlock())
+	defer queue.close()
+
+	logger.Info("之前")
+	logger.DPanic("立即寫出")
+
+	if got := sink.snapshot(); strings.Join(got, ",") != "之前,立即寫出" {
+		t.Fatalf("DPanic 回傳後寫出 = %v", got)
+	}
+}
+
+func TestInstanceAsyncDrainsOnClose(t *testing.T) {
+	base := t.TempDir()
+	cfg := fileOutputTestConfig(base, "")
+	cfg.Outputs = []string{"split"}
+	cfg.SplitPrefix = "app"
+	instance, err := NewWithOptions(cfg, WithAsync(1024, OverflowBlock()))
+	if err != nil {
+		t.Fatalf("建立 Instance 失敗：%v", err)
+	}
+	for range 100 {
+		instance.Logger().Warn("排入佇列")
+	}
+	if err := instance.Close(); err != nil {
+		t.Fatalf("關閉 Instance 失敗：%v", err)
+	}
+	if stats := instance.Stats(); stats.DroppedEntries != 0 {
+		t.Fatalf("Stats = %+v，預期未丟棄", stats)
+	}
+
+	warnFiles, err := filepath.Glob(filepath.Join(base, "app-warn-*.log"))
+	if err != nil || len(warnFiles) != 1 {
+		t.Fatalf("warn 檔案 = %v, %v", warnFiles, err)
+	}
+	//nolint:gosec // 測試只讀取 t.TempDir 內的預期檔案。
+	content, err := os.ReadFile(warnFiles[0])
+	if err != nil {
+		t.Fatalf("讀取日誌失敗：%v", err)
+	}
+	if count := strings.Count(string(content), "排入佇列"); count != 100 {
+		t.Fatalf("warn 檔案有 %d 筆，預期 100", count)
+	}
+	if strings.Contains(string(content), "logger initialized") {
+		t.Fatal("非同步寫入不應改變分級路由")
+	}
+}
+
+func TestInstanceAsyncReportsSinkWriteFailures(t *testing.T) {
+	stdout, err := os.Create(filepath.Join(t.TempDir(), "stdout"))
+	if err != nil {
+		t.Fatalf("建立 stdout 檔案失敗：%v", err)
+	}
+	if err := stdout.Close(); err != nil {
+		t.Fatalf("關閉 stdout 檔案失敗：%v", err)
+	}
+	originalStdout := os.Stdout
+	os.Stdout = stdout
+	t.Cleanup(func() { os.Stdout = originalStdout })
+
+	cfg := fileOutputTestConfig(t.TempDir(), "")
+	cfg.Outputs = []string{"console"}
+	handler := &recordingErrorHandler{}
+	instance, err := NewWithOptions(cfg, WithAsync(8, OverflowBlock()), WithErrorHandler(handler.handle))
+	if err != nil {
+		t.Fatalf("建立 Instance 失敗：%v", err)
+	}
+	instance.Logger().Info("寫入已關閉的 stdout")
+	_ = instance.Close()
+
+	events := handler.snapshot()
+	if len(events) == 0 {
+		t.Fatal("背景寫出失敗應交給 ErrorHandler")
+	}
+	for _, event := range events {
+		if event.Kind != InternalEventWrite || !strings.Contains(event.Err.Error(), "file already closed") {
+			t.Fatalf("事件 = %+v，預期 console 寫入失敗", event)
+		}
+	}
+}
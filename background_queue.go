@@ -0,0 +1,108 @@
+package zlogger
+
+import (
+	"sync"
+	"time"
+)
+
+// backgroundQueue 以單一 goroutine 依序執行背景工作。
+//
+// close 會拒絕新工作，並在執行完所有已排入的工作後才回傳；closeWithin 另設等待上限。
+// limit 大於 0 時，尚未開始的工作達 limit 筆後 enqueue 拒絕新工作。
+type backgroundQueue struct {
+	mu      sync.Mutex
+	pending []func()
+	limit   int
+	closed  bool
+	wake    chan struct{}
+	done    chan struct{}
+}
+
+func newBackgroundQueue(limit int) *backgroundQueue {
+	queue := &backgroundQueue{
+		limit: limit,
+		wake:  make(chan struct{}, 1),
+		done:  make(chan struct{}),
+	}
+	go queue.run()
+	return queue
+}
+
+// enqueue 排入工作且不阻塞呼叫端；queue 已關閉或已滿時回傳 false。
+func (q *backgroundQueue) enqueue(task func()) bool {
+	q.mu.Lock()
+	if q.closed || (q.limit > 0 && len(q.pending) >= q.limit) {
+		q.mu.Unlock()
+		return false
+	}
+	q.pending = append(q.pending, task)
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	q.mu.Unlock()
+	return true
+}
+
+// close 停止接受工作並等待既有工作執行完畢，可重複呼叫。
+func (q *backgroundQueue) close() {
+	if q == nil {
+		return
+	}
+	q.stop()
+	<-q.done
+}
+
+// closeWithin 與 close 相同，但最多等待 timeout。逾時時捨棄尚未開始的工作並回傳其筆數，
+// 執行中的工作不會被中斷，ok 為 false。
+func (q *backgroundQueue) closeWithin(timeout time.Duration) (abandoned int, ok bool) {
+	if q == nil {
+		return 0, true
+	}
+	q.stop()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-q.done:
+		return 0, true
+	case <-timer.C:
+	}
+	q.mu.Lock()
+	abandoned = len(q.pending)
+	q.pending = nil
+	q.mu.Unlock()
+	return abandoned, false
+}
+
+func (q *backgroundQueue) stop() {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.wake)
+	}
+	q.mu.Unlock()
+}
+
+func (q *backgroundQueue) run() {
+	defer close(q.done)
+
+	for {
+		_, open := <-q.wake
+		for {
+			q.mu.Lock()
+			if len(q.pending) == 0 {
+				q.mu.Unlock()
+				break
+			}
+			task := q.pending[0]
+			q.pending[0] = nil
+			q.pending = q.pending[1:]
+			q.mu.Unlock()
+			task()
+		}
+		if !open {
+			return
+		}
+	}
+}
@@ -173,6 +173,34 @@ func BenchmarkLoggerSplitOutputWrite(b *testing.B) {
 	})
 }
 
+func BenchmarkLoggerSplitOutputFileWrite(b *testing.B) {
+	payload := []byte("{\"level\":\"info\",\"message\":\"request completed\",\"request_id\":\"req-1234567890\"}\n")
+	options := map[string][]FileOutputOption{
+		"unbuffered": nil,
+		"buffered":   {WithBufferedWrites(256*1024, time.Second)},
+	}
+
+	for _, name := range []string{"unbuffered", "buffered"} {
+		b.Run(name, func(b *testing.B) {
+			output, err := NewSplitOutputWithOptions(b.TempDir(), "bench", options[name]...)
+			if err != nil {
+				b.Fatalf("建立 SplitOutput 失敗：%v", err)
+			}
+			b.Cleanup(func() { _ = output.Close() })
+
+			b.ReportAllocs()
+			b.SetBytes(int64(len(payload)))
+			b.ResetTimer()
+			for b.Loop() {
+				written, err := output.Write(zapcore.InfoLevel, payload)
+				if err != nil || written != len(payload) {
+					b.Fatalf("SplitOutput.Write 寫入結果 = (%d, %v)，預期 (%d, nil)", written, err, len(payload))
+				}
+			}
+		})
+	}
+}
+
 func newBenchmarkLogger(level zapcore.Level) *zap.Logger {
 	cfg := DefaultConfig()
 	cfg.Format = "json"
@@ -202,8 +230,10 @@ func benchmarkContextFields(count int) []zap.Field {
 
 func newBenchmarkSplitOutput() *SplitOutput {
 	return &SplitOutput{
-		infoOut:  benchmarkWriteSyncCloser{},
-		warnOut:  benchmarkWriteSyncCloser{},
-		errorOut: benchmarkWriteSyncCloser{},
+		outputs: splitFileSet{
+			benchmarkWriteSyncCloser{},
+			benchmarkWriteSyncCloser{},
+			benchmarkWriteSyncCloser{},
+		},
 	}
 }
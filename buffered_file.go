@@ -0,0 +1,123 @@
+package zlogger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// WithBufferedWrites 以 size bytes 的記憶體緩衝累積日誌，並每隔 flushInterval 寫入檔案。
+//
+// 適用於 file output 與 SplitOutput。緩衝只在完整日誌之間寫出，單筆日誌不會被拆到
+// 不同檔案；單筆大於等於 size 時直接寫入。Sync、換檔、Reopen 與 Close 會先寫出緩衝，
+// 換檔前累積的日誌仍落在原週期的檔案。程序異常結束時，最多遺失一個 flushInterval
+// 內尚未寫出的日誌；寫出失敗時丟棄緩衝內容並以 InternalEventWrite 回報。
+func WithBufferedWrites(size int, flushInterval time.Duration) FileOutputOption {
+	return fileOutputOptionFunc(func(settings *fileOutputSettings) error {
+		if size <= 0 {
+			return fmt.Errorf("%w: 寫入緩衝大小必須大於 0", ErrInvalidConfig)
+		}
+		if flushInterval <= 0 {
+			return fmt.Errorf("%w: 緩衝寫出間隔必須大於 0", ErrInvalidConfig)
+		}
+		settings.bufferSize = size
+		settings.flushInterval = flushInterval
+		return nil
+	})
+}
+
+// bufferedFile 在記憶體累積完整日誌，緩衝將滿、Flush、Sync 或 Close 時才寫入底層檔案。
+//
+// 所有方法都必須在擁有者的 mutex 保護下呼叫。
+type bufferedFile struct {
+	file   writeSyncCloser
+	size   int
+	buffer []byte
+}
+
+func newBufferedFile(file writeSyncCloser, size int) *bufferedFile {
+	return &bufferedFile{file: file, size: size, buffer: make([]byte, 0, size)}
+}
+
+func (f *bufferedFile) Write(data []byte) (int, error) {
+	if len(f.buffer)+len(data) > f.size {
+		if err := f.Flush(); err != nil {
+			return 0, err
+		}
+	}
+	if len(data) >= f.size {
+		return f.file.Write(data)
+	}
+	f.buffer = append(f.buffer, data...)
+	return len(data), nil
+}
+
+// Flush 將緩衝內容寫入底層檔案；失敗時丟棄緩衝，避免後續寫入重複失敗。
+func (f *bufferedFile) Flush() error {
+	if len(f.buffer) == 0 {
+		return nil
+	}
+	_, err := f.file.Write(f.buffer)
+	f.buffer = f.buffer[:0]
+	return err
+}
+
+// Stat 回傳底層 handle 的檔案資訊，供檔案識別檢查使用。
+func (f *bufferedFile) Stat() (os.FileInfo, error) {
+	stater, ok := f.file.(fileStater)
+	if !ok {
+		return nil, fmt.Errorf("緩衝日誌檔不支援 Stat: %w", os.ErrInvalid)
+	}
+	return stater.Stat()
+}
+
+func (f *bufferedFile) Sync() error {
+	return errors.Join(f.Flush(), f.file.Sync())
+}
+
+func (f *bufferedFile) Close() error {
+	return errors.Join(f.Flush(), f.file.Close())
+}
+
+// flushFile 寫出 file 的緩衝；未啟用緩衝的檔案直接略過。
+func flushFile(file writeSyncCloser) error {
+	if buffered, ok := file.(*bufferedFile); ok {
+		return buffered.Flush()
+	}
+	return nil
+}
+
+// withBuffer 為每個分級檔案套用寫入緩衝；未啟用時原樣回傳。
+func (s *SplitOutput) withBuffer(files splitFileSet) splitFileSet {
+	size := s.settings.bufferSize
+	if size <= 0 {
+		return files
+	}
+	buffered := make(splitFileSet, len(files))
+	for index, file := range files {
+		if file != nil {
+			buffered[index] = newBufferedFile(file, size)
+		}
+	}
+	return buffered
+}
+
+// flushOnSchedule 定期寫出緩衝，直到 Close 關閉 stop。
+func (s *SplitOutput) flushOnSchedule() {
+	defer close(s.flushDone)
+	runEvery(s.clock, s.settings.flushInterval, s.stop, s.flushBuffers)
+}
+
+// flushBuffers 寫出所有分級檔案的緩衝，失敗於釋放 mutex 後回報。
+func (s *SplitOutput) flushBuffers() {
+	s.mutex.Lock()
+	if s.closed {
+		s.mutex.Unlock()
+		return
+	}
+	events := s.outputs.failures(InternalEventWrite, s.directory, s.leaves, flushFile)
+	s.mutex.Unlock()
+
+	s.reportInternalEvents(events)
+}
@@ -0,0 +1,146 @@
+package zlogger
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+type chunkRecorder struct {
+	chunks []string
+	closed bool
+}
+
+func (r *chunkRecorder) Write(p []byte) (int, error) {
+	r.chunks = append(r.chunks, string(p))
+	return len(p), nil
+}
+
+func (r *chunkRecorder) Sync() error {
+	return nil
+}
+
+func (r *chunkRecorder) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestWithBufferedWritesRejectsInvalidSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		size     int
+		interval time.Duration
+	}{
+		{name: "緩衝為 0", size: 0, interval: time.Second},
+		{name: "間隔為 0", size: 4096, interval: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := filepath.Join(t.TempDir(), "不應建立")
+			output, err := NewSplitOutputWithOptions(base, "app", WithBufferedWrites(tt.size, tt.interval))
+			if output != nil {
+				_ = output.Close()
+				t.Fatal("無效設定不應回傳 SplitOutput")
+			}
+			if !errors.Is(err, ErrInvalidConfig) {
+				t.Fatalf("錯誤 = %v，預期 ErrInvalidConfig", err)
+			}
+			assertPathDoesNotExist(t, base)
+		})
+	}
+}
+
+func TestBufferedFileWritesWholeEntries(t *testing.T) {
+	recorder := &chunkRecorder{}
+	file := newBufferedFile(recorder, 10)
+
+	for _, entry := range []string{"abcd\n", "efgh\n", "ij\n", "0123456789ab\n"} {
+		if written, err := file.Write([]byte(entry)); err != nil || written != len(entry) {
+			t.Fatalf("寫入 %q = %d, %v", entry, written, err)
+		}
+	}
+	if err := file.Close(); err != nil {
+		t.Fatalf("關閉失敗：%v", err)
+	}
+
+	want := []string{"abcd\nefgh\n", "ij\n", "0123456789ab\n"}
+	if strings.Join(recorder.chunks, "|") != strings.Join(want, "|") || !recorder.closed {
+		t.Fatalf("寫出 = %q, closed = %v，預期 %q", recorder.chunks, recorder.closed, want)
+	}
+}
+
+func TestSplitOutputBufferedWritesFlushBeforeRotation(t *testing.T) {
+	base := t.TempDir()
+	output, _ := newHookTestOutput(t, base, WithBufferedWrites(4096, time.Hour))
+
+	if _, err := output.Write(zapcore.InfoLevel, []byte("前一天\n")); err != nil {
+		t.Fatalf("寫入失敗：%v", err)
+	}
+	previous := filepath.Join(base, "app-info-2026-07-29.log")
+	assertFileContent(t, previous, "")
+
+	if err := output.openFilesAt(time.Date(2026, time.July, 30, 0, 0, 0, 0, time.Local)); err != nil {
+		t.Fatalf("換檔失敗：%v", err)
+	}
+	assertFileContent(t, previous, "前一天\n")
+
+	if _, err := output.Write(zapcore.InfoLevel, []byte("隔天\n")); err != nil {
+		t.Fatalf("寫入失敗：%v", err)
+	}
+	output.flushBuffers()
+	assertFileContent(t, filepath.Join(base, "app-info-2026-07-30.log"), "隔天\n")
+}
+
+func TestNewFileOutputBufferedWritesFlushPeriodically(t *testing.T) {
+	base := t.TempDir()
+	instance, err := NewWithOptions(fileOutputTestConfig(base, "app.log"), WithBufferedWrites(64*1024, 10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("建立 Instance 失敗：%v", err)
+	}
+	t.Cleanup(func() { _ = instance.Close() })
+	instance.Logger().Info("定期寫出")
+
+	path := filepath.Join(base, "app.log")
+	deadline := time.Now().Add(time.Second)
+	for {
+		//nolint:gosec // 測試只讀取 t.TempDir 內的預期檔案。
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("讀取日誌失敗：%v", err)
+		}
+		if strings.Contains(string(content), "定期寫出") {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("等待緩衝定期寫出逾時")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestNewFileOutputBufferedWritesFlushOnClose(t *testing.T) {
+	base := t.TempDir()
+	instance, err := NewWithOptions(fileOutputTestConfig(base, "app.log"), WithBufferedWrites(64*1024, time.Hour))
+	if err != nil {
+		t.Fatalf("建立 Instance 失敗：%v", err)
+	}
+	instance.Logger().Info("關閉前寫出")
+	if err := instance.Close(); err != nil {
+		t.Fatalf("關閉 Instance 失敗：%v", err)
+	}
+
+	//nolint:gosec // 測試只讀取 t.TempDir 內的預期檔案。
+	content, err := os.ReadFile(filepath.Join(base, "app.log"))
+	if err != nil {
+		t.Fatalf("讀取日誌失敗：%v", err)
+	}
+	if !strings.Contains(string(content), "關閉前寫出") {
+		t.Fatalf("日誌內容 = %s，預期包含緩衝內容", content)
+	}
+}
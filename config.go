@@ -20,23 +20,30 @@ type Config struct {
 	Outputs       []string `json:"outputs" yaml:"outputs" toml:"outputs" mapstructure:"outputs"`
 	LogPath       string   `json:"log_path" yaml:"log_path" toml:"log_path" mapstructure:"log_path"`
 	FileName      string   `json:"file_name" yaml:"file_name" toml:"file_name" mapstructure:"file_name"`
+	SplitPrefix   string   `json:"split_prefix" yaml:"split_prefix" toml:"split_prefix" mapstructure:"split_prefix"`
+	SplitFormat   string   `json:"split_format" yaml:"split_format" toml:"split_format" mapstructure:"split_format"`
 	AddCaller     bool     `json:"add_caller" yaml:"add_caller" toml:"add_caller" mapstructure:"add_caller"`
 	AddStacktrace bool     `json:"add_stacktrace" yaml:"add_stacktrace" toml:"add_stacktrace" mapstructure:"add_stacktrace"`
 	Development   bool     `json:"development" yaml:"development" toml:"development" mapstructure:"development"`
 	ColorEnabled  bool     `json:"color_enabled" yaml:"color_enabled" toml:"color_enabled" mapstructure:"color_enabled"`
+	// Sampling 為 nil 時不取樣。
+	Sampling *SamplingConfig `json:"sampling,omitempty" yaml:"sampling,omitempty" toml:"sampling,omitempty" mapstructure:"sampling"`
 }
 
 // ConfigPatch 表示可區分未提供與明確零值的部分設定。
 type ConfigPatch struct {
-	Level         *string   `json:"level,omitempty" yaml:"level,omitempty" toml:"level,omitempty" mapstructure:"level"`
-	Format        *string   `json:"format,omitempty" yaml:"format,omitempty" toml:"format,omitempty" mapstructure:"format"`
-	Outputs       *[]string `json:"outputs,omitempty" yaml:"outputs,omitempty" toml:"outputs,omitempty" mapstructure:"outputs"`
-	LogPath       *string   `json:"log_path,omitempty" yaml:"log_path,omitempty" toml:"log_path,omitempty" mapstructure:"log_path"`
-	FileName      *string   `json:"file_name,omitempty" yaml:"file_name,omitempty" toml:"file_name,omitempty" mapstructure:"file_name"`
-	AddCaller     *bool     `json:"add_caller,omitempty" yaml:"add_caller,omitempty" toml:"add_caller,omitempty" mapstructure:"add_caller"`
-	AddStacktrace *bool     `json:"add_stacktrace,omitempty" yaml:"add_stacktrace,omitempty" toml:"add_stacktrace,omitempty" mapstructure:"add_stacktrace"`
-	Development   *bool     `json:"development,omitempty" yaml:"development,omitempty" toml:"development,omitempty" mapstructure:"development"`
-	ColorEnabled  *bool     `json:"color_enabled,omitempty" yaml:"color_enabled,omitempty" toml:"color_enabled,omitempty" mapstructure:"color_enabled"`
+	Level         *string         `json:"level,omitempty" yaml:"level,omitempty" toml:"level,omitempty" mapstructure:"level"`
+	Format        *string         `json:"format,omitempty" yaml:"format,omitempty" toml:"format,omitempty" mapstructure:"format"`
+	Outputs       *[]string       `json:"outputs,omitempty" yaml:"outputs,omitempty" toml:"outputs,omitempty" mapstructure:"outputs"`
+	LogPath       *string         `json:"log_path,omitempty" yaml:"log_path,omitempty" toml:"log_path,omitempty" mapstructure:"log_path"`
+	FileName      *string         `json:"file_name,omitempty" yaml:"file_name,omitempty" toml:"file_name,omitempty" mapstructure:"file_name"`
+	SplitPrefix   *string         `json:"split_prefix,omitempty" yaml:"split_prefix,omitempty" toml:"split_prefix,omitempty" mapstructure:"split_prefix"`
+	SplitFormat   *string         `json:"split_format,omitempty" yaml:"split_format,omitempty" toml:"split_format,omitempty" mapstructure:"split_format"`
+	AddCaller     *bool           `json:"add_caller,omitempty" yaml:"add_caller,omitempty" toml:"add_caller,omitempty" mapstructure:"add_caller"`
+	AddStacktrace *bool           `json:"add_stacktrace,omitempty" yaml:"add_stacktrace,omitempty" toml:"add_stacktrace,omitempty" mapstructure:"add_stacktrace"`
+	Development   *bool           `json:"development,omitempty" yaml:"development,omitempty" toml:"development,omitempty" mapstructure:"development"`
+	ColorEnabled  *bool           `json:"color_enabled,omitempty" yaml:"color_enabled,omitempty" toml:"color_enabled,omitempty" mapstructure:"color_enabled"`
+	Sampling      *SamplingConfig `json:"sampling,omitempty" yaml:"sampling,omitempty" toml:"sampling,omitempty" mapstructure:"sampling"`
 }
 
 // DefaultConfig 回傳可直接使用的完整預設設定。
@@ -46,6 +53,7 @@ func DefaultConfig() *Config {
 		Format:        "console",
 		Outputs:       []string{"console"},
 		LogPath:       "./logs",
+		SplitPrefix:   "app",
 		AddCaller:     true,
 		AddStacktrace: false,
 		Development:   false,
@@ -75,6 +83,12 @@ func (p *ConfigPatch) Resolve() (*Config, error) {
 	if p.FileName != nil {
 		cfg.FileName = *p.FileName
 	}
+	if p.SplitPrefix != nil {
+		cfg.SplitPrefix = *p.SplitPrefix
+	}
+	if p.SplitFormat != nil {
+		cfg.SplitFormat = *p.SplitFormat
+	}
 	if p.AddCaller != nil {
 		cfg.AddCaller = *p.AddCaller
 	}
@@ -87,6 +101,9 @@ func (p *ConfigPatch) Resolve() (*Config, error) {
 	if p.ColorEnabled != nil {
 		cfg.ColorEnabled = *p.ColorEnabled
 	}
+	if p.Sampling != nil {
+		cfg.Sampling = p.Sampling
+	}
 
 	cfg = cfg.normalizedCopy()
 	if err := cfg.Validate(); err != nil {
@@ -97,16 +114,14 @@ func (p *ConfigPatch) Resolve() (*Config, error) {
 }
 
 // Validate 檢查完整設定，不修改呼叫端提供的物件。
-// file output 的 FileName 必須是安全 leaf name。
+// file output 的 FileName 與 split output 的 SplitPrefix 必須是安全 leaf name。
 func (c *Config) Validate() error {
 	if c == nil {
 		return fmt.Errorf("%w: Config 不可為 nil", ErrInvalidConfig)
 	}
 
-	switch strings.ToLower(c.Level) {
-	case "debug", "info", "warn", "error", "fatal":
-	default:
-		return fmt.Errorf("%w: Level %q 不受支援", ErrInvalidConfig, c.Level)
+	if _, err := parseLevelSpec(c.Level); err != nil {
+		return err
 	}
 
 	switch strings.ToLower(c.Format) {
@@ -121,12 +136,15 @@ func (c *Config) Validate() error {
 
 	seen := make(map[string]struct{}, len(c.Outputs))
 	fileEnabled := false
+	splitEnabled := false
 	for _, output := range c.Outputs {
 		normalized := strings.ToLower(output)
 		switch normalized {
 		case "console":
 		case "file":
 			fileEnabled = true
+		case "split":
+			splitEnabled = true
 		default:
 			return fmt.Errorf("%w: Output %q 不受支援", ErrInvalidConfig, output)
 		}
@@ -144,6 +162,24 @@ func (c *Config) Validate() error {
 			return fmt.Errorf("%w: FileName: %w", ErrInvalidConfig, err)
 		}
 	}
+	if splitEnabled && c.LogPath == "" {
+		return fmt.Errorf("%w: split output 的 LogPath 不可為空", ErrInvalidConfig)
+	}
+	if splitEnabled {
+		if err := validateLogLeaf(c.SplitPrefix, false); err != nil {
+			return fmt.Errorf("%w: SplitPrefix: %w", ErrInvalidConfig, err)
+		}
+		switch strings.ToLower(c.SplitFormat) {
+		case "", "console", "json":
+		default:
+			return fmt.Errorf("%w: SplitFormat %q 不受支援", ErrInvalidConfig, c.SplitFormat)
+		}
+	}
+	if c.Sampling != nil {
+		if err := c.Sampling.validate(); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
@@ -172,10 +208,19 @@ func (c *Config) Merge(other *Config) *Config {
 	if other.FileName != "" {
 		c.FileName = other.FileName
 	}
+	if other.SplitPrefix != "" {
+		c.SplitPrefix = other.SplitPrefix
+	}
+	if other.SplitFormat != "" {
+		c.SplitFormat = other.SplitFormat
+	}
 	c.AddCaller = other.AddCaller
 	c.AddStacktrace = other.AddStacktrace
 	c.Development = other.Development
 	c.ColorEnabled = other.ColorEnabled
+	if other.Sampling != nil {
+		c.Sampling = other.Sampling.clone()
+	}
 
 	return c
 }
@@ -186,9 +231,11 @@ func (c *Config) normalizedCopy() *Config {
 	}
 
 	cloned := *c
-	cloned.Level = strings.ToLower(c.Level)
+	cloned.Level = normalizeLevelSpec(c.Level)
 	cloned.Format = strings.ToLower(c.Format)
+	cloned.SplitFormat = strings.ToLower(c.SplitFormat)
 	cloned.Outputs = slices.Clone(c.Outputs)
+	cloned.Sampling = c.Sampling.clone()
 	for i := range cloned.Outputs {
 		cloned.Outputs[i] = strings.ToLower(cloned.Outputs[i])
 	}
@@ -0,0 +1,233 @@
+package zlogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// maxConfigFileSize 是設定檔大小上限。
+const maxConfigFileSize = 1 << 20
+
+// LoadConfigPatchFile 讀取 JSON 或 TOML 設定檔，回傳檔案內容的 ConfigPatch 與經
+// ConfigPatch.Resolve 補齊並驗證的 Config。
+//
+// 格式依副檔名 .json 或 .toml 決定，檔案最上層即 ConfigPatch 的 key。未知或重複的 key
+// 回傳包含行號與完整路徑（例如 sampling.initail）的錯誤。TOML 只支援 table、dotted key、
+// 字串、整數、bool、單行 array 與 inline table。內容錯誤包裝 ErrInvalidConfig；開啟或
+// 讀取檔案的錯誤保留原本的 os 錯誤。
+func LoadConfigPatchFile(path string) (*ConfigPatch, *Config, error) {
+	patch, err := readConfigPatchFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	cfg, err := patch.Resolve()
+	if err != nil {
+		return nil, nil, fmt.Errorf("設定檔 %s: %w", path, err)
+	}
+	return patch, cfg, nil
+}
+
+// readConfigPatchFile 讀取並解析設定檔，不執行 Resolve。
+func readConfigPatchFile(path string) (*ConfigPatch, error) {
+	if err := checkConfigFileExt(path); err != nil {
+		return nil, err
+	}
+	data, err := readConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseConfigPatch(path, data)
+}
+
+func checkConfigFileExt(path string) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json", ".toml":
+		return nil
+	default:
+		return fmt.Errorf("%w: 設定檔 %s 的副檔名必須是 .json 或 .toml", ErrInvalidConfig, path)
+	}
+}
+
+// parseConfigPatch 依 path 的副檔名解析 data。
+func parseConfigPatch(path string, data []byte) (*ConfigPatch, error) {
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		return parseTOMLConfigPatch(path, data)
+	}
+	return parseJSONConfigPatch(path, data)
+}
+
+// readConfigFile 讀取設定檔內容，超過 maxConfigFileSize 時回傳錯誤。
+func readConfigFile(path string) ([]byte, error) {
+	//nolint:gosec // 設定檔路徑由呼叫端提供。
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("開啟設定檔: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxConfigFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("讀取設定檔 %s: %w", path, err)
+	}
+	if len(data) > maxConfigFileSize {
+		return nil, fmt.Errorf("%w: 設定檔 %s 超過 %d bytes", ErrInvalidConfig, path, maxConfigFileSize)
+	}
+	return data, nil
+}
+
+// configKeyLines 記錄每個 key 路徑所在的行號，供錯誤訊息使用。
+type configKeyLines map[string]int
+
+func (l configKeyLines) locate(file, path string) string {
+	if line, ok := l[path]; ok {
+		return fmt.Sprintf("設定檔 %s 第 %d 行 %s", file, line, path)
+	}
+	return fmt.Sprintf("設定檔 %s 的 %s", file, path)
+}
+
+// configChildType 依 json tag 回傳 key 對應的型別；t 不是 struct 或 map 時不檢查 key。
+func configChildType(t reflect.Type, key string) (reflect.Type, bool) {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil {
+		return nil, true
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		for index := range t.NumField() {
+			field := t.Field(index)
+			if jsonFieldKey(field) == key {
+				return field.Type, true
+			}
+		}
+		return nil, false
+	case reflect.Map:
+		return t.Elem(), true
+	default:
+		return nil, true
+	}
+}
+
+// jsonFieldKey 回傳欄位 json tag 的名稱。
+func jsonFieldKey(field reflect.StructField) string {
+	key, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	return key
+}
+
+func joinConfigPath(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+// decodeConfigPatch 以嚴格 JSON decoder 填入 ConfigPatch，型別錯誤以 lines 指出位置。
+func decodeConfigPatch(file string, data []byte, lines configKeyLines) (*ConfigPatch, error) {
+	var patch ConfigPatch
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&patch); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) && typeErr.Field != "" {
+			return nil, fmt.Errorf("%w: %s 的型別應為 %s，實際為 %s",
+				ErrInvalidConfig, lines.locate(file, typeErr.Field), typeErr.Type, typeErr.Value)
+		}
+		return nil, fmt.Errorf("%w: 設定檔 %s: %w", ErrInvalidConfig, file, err)
+	}
+	return &patch, nil
+}
+
+// parseJSONConfigPatch 先逐 token 檢查未知與重複的 key，再解碼為 ConfigPatch。
+func parseJSONConfigPatch(file string, data []byte) (*ConfigPatch, error) {
+	walker := &jsonKeyWalker{
+		file:    file,
+		data:    data,
+		decoder: json.NewDecoder(bytes.NewReader(data)),
+		lines:   make(configKeyLines),
+	}
+	if err := walker.value(reflect.TypeFor[ConfigPatch](), ""); err != nil {
+		return nil, err
+	}
+	if _, err := walker.decoder.Token(); !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: 設定檔 %s 只能包含一個 JSON 物件", ErrInvalidConfig, file)
+	}
+	return decodeConfigPatch(file, data, walker.lines)
+}
+
+type jsonKeyWalker struct {
+	file    string
+	data    []byte
+	decoder *json.Decoder
+	lines   configKeyLines
+}
+
+// line 回傳 decoder 目前位置的行號。
+func (w *jsonKeyWalker) line() int {
+	return bytes.Count(w.data[:w.decoder.InputOffset()], []byte("\n")) + 1
+}
+
+func (w *jsonKeyWalker) token() (json.Token, error) {
+	token, err := w.decoder.Token()
+	if err != nil {
+		return nil, fmt.Errorf("%w: 設定檔 %s 第 %d 行: %w", ErrInvalidConfig, w.file, w.line(), err)
+	}
+	return token, nil
+}
+
+func (w *jsonKeyWalker) value(t reflect.Type, path string) error {
+	token, err := w.token()
+	if err != nil {
+		return err
+	}
+	switch token {
+	case json.Delim('{'):
+		seen := make(map[string]struct{})
+		for w.decoder.More() {
+			token, err := w.token()
+			if err != nil {
+				return err
+			}
+			key, _ := token.(string)
+			child := joinConfigPath(path, key)
+			if _, exists := seen[key]; exists {
+				return fmt.Errorf("%w: 設定檔 %s 第 %d 行: key %q 重複", ErrInvalidConfig, w.file, w.line(), child)
+			}
+			seen[key] = struct{}{}
+			childType, known := configChildType(t, key)
+			if !known {
+				return fmt.Errorf("%w: 設定檔 %s 第 %d 行: 未知的 key %q", ErrInvalidConfig, w.file, w.line(), child)
+			}
+			w.lines[child] = w.line()
+			if err := w.value(childType, child); err != nil {
+				return err
+			}
+		}
+		_, err = w.token()
+		return err
+	case json.Delim('['):
+		var elem reflect.Type
+		if t != nil && t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t != nil && t.Kind() == reflect.Slice {
+			elem = t.Elem()
+		}
+		for w.decoder.More() {
+			if err := w.value(elem, path); err != nil {
+				return err
+			}
+		}
+		_, err = w.token()
+		return err
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,228 @@
+package zlogger
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("寫入設定檔失敗：%v", err)
+	}
+	return path
+}
+
+func assertLoadedConfig(t *testing.T, patch *ConfigPatch, cfg *Config) {
+	t.Helper()
+	if patch.Level == nil || patch.Format == nil || patch.FileName != nil {
+		t.Fatalf("patch = %+v，預期只填入檔案中的 key", patch)
+	}
+	if cfg.Level != "debug,db=warn" || cfg.Format != "json" || cfg.LogPath != "/var/log/app" || cfg.AddCaller {
+		t.Fatalf("Config = %+v", cfg)
+	}
+	if !slices.Equal(cfg.Outputs, []string{"console", "split"}) || cfg.SplitPrefix != "svc" {
+		t.Fatalf("Outputs = %v, SplitPrefix = %q", cfg.Outputs, cfg.SplitPrefix)
+	}
+	if cfg.Sampling == nil || cfg.Sampling.Initial != 100 || cfg.Sampling.Tick != "500ms" ||
+		cfg.Sampling.Levels["error"] != (SamplingLevel{Initial: 1000, Thereafter: 10}) {
+		t.Fatalf("Sampling = %+v", cfg.Sampling)
+	}
+}
+
+func TestLoadConfigPatchFileJSON(t *testing.T) {
+	path := writeConfigFile(t, "log.json", `{
+  "level": "DEBUG,db=warn",
+  "format": "json",
+  "outputs": ["console", "split"],
+  "log_path": "/var/log/app",
+  "split_prefix": "svc",
+  "add_caller": false,
+  "sampling": {
+    "initial": 100,
+    "tick": "500ms",
+    "levels": {"error": {"initial": 1000, "thereafter": 10}}
+  }
+}`)
+
+	patch, cfg, err := LoadConfigPatchFile(path)
+	if err != nil {
+		t.Fatalf("LoadConfigPatchFile 失敗：%v", err)
+	}
+	assertLoadedConfig(t, patch, cfg)
+}
+
+func TestLoadConfigPatchFileTOML(t *testing.T) {
+	path := writeConfigFile(t, "log.toml", `# logger 設定
+level = "DEBUG,db=warn"
+format = 'json'  # 行尾註解
+outputs = ["console", "split",]
+log_path = "/var/log/app"
+split_prefix = "svc"
+add_caller = false
+
+[sampling]
+initial = 1_00
+tick = "500ms"
+levels.error = { initial = 1000, thereafter = 10 }
+`)
+
+	patch, cfg, err := LoadConfigPatchFile(path)
+	if err != nil {
+		t.Fatalf("LoadConfigPatchFile 失敗：%v", err)
+	}
+	assertLoadedConfig(t, patch, cfg)
+}
+
+func TestLoadConfigPatchFileRejectsUnknownKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    string
+	}{
+		{
+			name:    "JSON 最上層",
+			file:    "log.json",
+			content: "{\n  \"level\": \"info\",\n  \"add_caler\": true\n}",
+			want:    `第 3 行: 未知的 key "add_caler"`,
+		},
+		{
+			name:    "JSON 巢狀",
+			file:    "log.json",
+			content: "{\n  \"sampling\": {\n    \"initial\": 1,\n    \"levels\": {\"warn\": {\"initail\": 1}}\n  }\n}",
+			want:    `第 4 行: 未知的 key "sampling.levels.warn.initail"`,
+		},
+		{
+			name:    "JSON 重複",
+			file:    "log.json",
+			content: "{\"level\": \"info\",\n\"level\": \"debug\"}",
+			want:    `第 2 行: key "level" 重複`,
+		},
+		{
+			name:    "TOML 最上層",
+			file:    "log.toml",
+			content: "level = \"info\"\n\nadd_caler = true\n",
+			want:    `第 3 行: 未知的 key "add_caler"`,
+		},
+		{
+			name:    "TOML table",
+			file:    "log.toml",
+			content: "[sampling]\ninitial = 1\n\n[sampling.levels.warn]\ninitail = 1\n",
+			want:    `第 5 行: 未知的 key "sampling.levels.warn.initail"`,
+		},
+		{
+			name:    "TOML 未知 table",
+			file:    "log.toml",
+			content: "level = \"info\"\n[sampler]\ninitial = 1\n",
+			want:    `第 2 行: 未知的 key "sampler"`,
+		},
+		{
+			name:    "TOML 重複",
+			file:    "log.toml",
+			content: "level = \"info\"\nlevel = \"debug\"\n",
+			want:    `第 2 行: key "level" 重複`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch, cfg, err := LoadConfigPatchFile(writeConfigFile(t, tt.file, tt.content))
+			if patch != nil || cfg != nil || !errors.Is(err, ErrInvalidConfig) {
+				t.Fatalf("回傳 %+v, %+v, %v，預期 ErrInvalidConfig", patch, cfg, err)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("錯誤 %q 未包含 %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigPatchFileRejectsInvalidContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    string
+	}{
+		{name: "JSON 型別錯誤", file: "log.json", content: "{\n\"add_caller\": \"yes\"}", want: "第 2 行 add_caller"},
+		{name: "JSON 多個物件", file: "log.json", content: `{"level":"info"} {}`, want: "只能包含一個"},
+		{name: "JSON 語法錯誤", file: "log.json", content: "{\n\"level\": }", want: "第 2 行"},
+		{name: "TOML 型別錯誤", file: "log.toml", content: "\n[sampling]\ninitial = \"1\"\n", want: "第 3 行 sampling.initial"},
+		{name: "TOML 浮點數", file: "log.toml", content: "[sampling]\ninitial = 1.5\n", want: "第 2 行"},
+		{name: "TOML 多行 array", file: "log.toml", content: "outputs = [\n  \"console\",\n]\n", want: "第 1 行"},
+		{name: "TOML array of tables", file: "log.toml", content: "[[sampling]]\n", want: "array of tables"},
+		{name: "TOML 前導零", file: "log.toml", content: "[sampling]\ninitial = 007\n", want: "第 2 行"},
+		{name: "TOML Go 跳脫字元", file: "log.toml", content: "level = \"\\x41\"\n", want: "跳脫字元無效"},
+		{name: "TOML 多餘內容", file: "log.toml", content: "level = \"info\" x\n", want: "多餘內容"},
+		{name: "驗證失敗", file: "log.json", content: `{"outputs": ["syslog"]}`, want: "syslog"},
+		{name: "副檔名", file: "log.yaml", content: "level: info\n", want: ".json 或 .toml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := LoadConfigPatchFile(writeConfigFile(t, tt.file, tt.content))
+			if !errors.Is(err, ErrInvalidConfig) || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("錯誤 = %v，預期包含 %q 的 ErrInvalidConfig", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTOMLCursorScalars(t *testing.T) {
+	tests := []struct {
+		text string
+		want any
+	}{
+		{text: `"a\tb\\c\"d"`, want: "a\tb\\c\"d"},
+		{text: `"\e[0m"`, want: "\x1b[0m"},
+		{text: `"\u00e9\U0001F600"`, want: "é😀"},
+		{text: `'C:\logs\x41'`, want: `C:\logs\x41`},
+		{text: "0", want: int64(0)},
+		{text: "+0", want: int64(0)},
+		{text: "-1_000", want: int64(-1000)},
+	}
+	for _, tt := range tests {
+		value, err := (&tomlCursor{text: tt.text}).value()
+		if err != nil || value != tt.want {
+			t.Errorf("%s = %#v, %v，預期 %#v", tt.text, value, err, tt.want)
+		}
+	}
+
+	invalid := []struct {
+		text string
+		want string
+	}{
+		{text: `"\x41"`, want: "跳脫字元無效"},
+		{text: `"\a"`, want: "跳脫字元無效"},
+		{text: `"\101"`, want: "跳脫字元無效"},
+		{text: `"\'"`, want: "跳脫字元無效"},
+		{text: `"\u12"`, want: "跳脫字元無效"},
+		{text: `"\uD800"`, want: "跳脫字元無效"},
+		{text: "007", want: "整數"},
+		{text: "+00", want: "整數"},
+		{text: "+_1", want: "整數"},
+		{text: "1_", want: "整數"},
+		{text: "1__0", want: "整數"},
+		{text: "-", want: "整數"},
+	}
+	for _, tt := range invalid {
+		value, err := (&tomlCursor{text: tt.text}).value()
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s = %#v, %v，預期包含 %q 的錯誤", tt.text, value, err, tt.want)
+		}
+	}
+}
+
+func TestLoadConfigPatchFileKeepsIOErrors(t *testing.T) {
+	_, _, err := LoadConfigPatchFile(filepath.Join(t.TempDir(), "missing.json"))
+	if !errors.Is(err, fs.ErrNotExist) || errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("錯誤 = %v，預期 fs.ErrNotExist 且非 ErrInvalidConfig", err)
+	}
+}
@@ -0,0 +1,172 @@
+package zlogger
+
+import (
+	"flag"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ConfigFlags 保存 BindConfigFlags 註冊的 flag 中，使用者實際傳入的欄位。
+type ConfigFlags struct {
+	prefix string
+	patch  ConfigPatch
+
+	sampling    SamplingConfig
+	samplingSet bool
+	initialSet  bool
+}
+
+// BindConfigFlags 在 fs 註冊所有 ConfigPatch 欄位，名稱為 prefix 加上 "." 與 key，
+// 例如 prefix 為 "log" 時註冊 -log.level、-log.outputs、-log.add-caller 與
+// -log.sampling.initial；prefix 為空字串時不加前綴。
+//
+// 只有實際傳入的 flag 會出現在 Patch 中，未傳入的欄位維持 nil。bool flag 只接受
+// true、false、1、0，可省略值表示 true；outputs 以逗號分隔。格式錯誤由 fs.Parse 回報，
+// 欄位值的其餘檢查由 Resolve 執行。
+func BindConfigFlags(fs *flag.FlagSet, prefix string) *ConfigFlags {
+	if prefix != "" && !strings.HasSuffix(prefix, ".") {
+		prefix += "."
+	}
+	flags := &ConfigFlags{prefix: prefix}
+	patch := &flags.patch
+
+	flags.define(fs, "level", "日誌級別規格，例如 info 或 info,db=debug", false, func(value string) error {
+		if _, err := parseLevelSpec(value); err != nil {
+			return err
+		}
+		patch.Level = &value
+		return nil
+	})
+	flags.defineString(fs, "format", "輸出格式：console 或 json", &patch.Format)
+	flags.define(fs, "outputs", "以逗號分隔的輸出：console、file、split", false, func(value string) error {
+		outputs, err := parseConfigList(value)
+		if err != nil {
+			return err
+		}
+		patch.Outputs = &outputs
+		return nil
+	})
+	flags.defineString(fs, "log-path", "file 與 split output 的目錄", &patch.LogPath)
+	flags.defineString(fs, "file-name", "file output 的檔名；空字串使用日期命名", &patch.FileName)
+	flags.defineString(fs, "split-prefix", "split output 的檔名前綴", &patch.SplitPrefix)
+	flags.defineString(fs, "split-format", "split output 的格式；空字串沿用 format", &patch.SplitFormat)
+	flags.defineBool(fs, "add-caller", "加入 caller", &patch.AddCaller)
+	flags.defineBool(fs, "add-stacktrace", "加入 ERROR 以上 stacktrace", &patch.AddStacktrace)
+	flags.defineBool(fs, "development", "啟用 zap development mode", &patch.Development)
+	flags.defineBool(fs, "color-enabled", "console format 使用 ANSI 色碼", &patch.ColorEnabled)
+
+	flags.define(fs, "sampling.initial", "每個 tick 內全部寫入的筆數；設定任一取樣 flag 時必須提供", false,
+		func(value string) error {
+			initial, err := parseConfigInt(value)
+			if err != nil {
+				return err
+			}
+			flags.sampling.Initial = initial
+			flags.samplingSet, flags.initialSet = true, true
+			return nil
+		})
+	flags.define(fs, "sampling.thereafter", "超過 initial 後每幾筆寫入一筆；0 表示全部丟棄", false,
+		func(value string) error {
+			thereafter, err := parseConfigInt(value)
+			if err != nil {
+				return err
+			}
+			flags.sampling.Thereafter = thereafter
+			flags.samplingSet = true
+			return nil
+		})
+	flags.define(fs, "sampling.tick", "取樣週期，time.ParseDuration 格式", false, func(value string) error {
+		if err := checkSamplingTick(value); err != nil {
+			return err
+		}
+		flags.sampling.Tick = value
+		flags.samplingSet = true
+		return nil
+	})
+	flags.define(fs, "sampling.levels", "級別覆寫，例如 error=1000:10,warn=100:0", false, func(value string) error {
+		levels, err := parseSamplingLevels(value)
+		if err != nil {
+			return err
+		}
+		flags.sampling.Levels = levels
+		flags.samplingSet = true
+		return nil
+	})
+	return flags
+}
+
+func (f *ConfigFlags) define(fs *flag.FlagSet, key, usage string, isBool bool, set func(string) error) {
+	fs.Var(&configFlagValue{set: set, isBool: isBool}, f.prefix+key, usage)
+}
+
+func (f *ConfigFlags) defineString(fs *flag.FlagSet, key, usage string, target **string) {
+	f.define(fs, key, usage, false, func(value string) error {
+		*target = &value
+		return nil
+	})
+}
+
+func (f *ConfigFlags) defineBool(fs *flag.FlagSet, key, usage string, target **bool) {
+	f.define(fs, key, usage, true, func(value string) error {
+		parsed, err := parseStrictBool(value)
+		if err != nil {
+			return err
+		}
+		*target = &parsed
+		return nil
+	})
+}
+
+// Patch 回傳實際傳入之 flag 組成的獨立 ConfigPatch，應於 fs.Parse 之後呼叫。
+//
+// 傳入取樣 flag 卻未提供 sampling.initial 時回傳包裝 ErrInvalidConfig 的錯誤。
+func (f *ConfigFlags) Patch() (*ConfigPatch, error) {
+	if f.samplingSet && !f.initialSet {
+		return nil, fmt.Errorf("%w: flag -%ssampling.initial %w", ErrInvalidConfig, f.prefix, errSamplingInitialRequired)
+	}
+	patch := f.patch
+	if patch.Outputs != nil {
+		outputs := slices.Clone(*patch.Outputs)
+		patch.Outputs = &outputs
+	}
+	if f.samplingSet {
+		patch.Sampling = f.sampling.clone()
+	}
+	return &patch, nil
+}
+
+// Resolve 以 Patch 套用預設值並驗證，回傳完整設定。
+func (f *ConfigFlags) Resolve() (*Config, error) {
+	patch, err := f.Patch()
+	if err != nil {
+		return nil, err
+	}
+	return patch.Resolve()
+}
+
+// configFlagValue 是只在 Set 時寫入目標欄位的 flag.Value。
+type configFlagValue struct {
+	set    func(string) error
+	value  string
+	isBool bool
+}
+
+func (v *configFlagValue) String() string {
+	if v == nil {
+		return ""
+	}
+	return v.value
+}
+
+func (v *configFlagValue) Set(value string) error {
+	if err := v.set(value); err != nil {
+		return err
+	}
+	v.value = value
+	return nil
+}
+
+func (v *configFlagValue) IsBoolFlag() bool {
+	return v.isBool
+}
@@ -0,0 +1,125 @@
+package zlogger
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func newConfigFlagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+func TestBindConfigFlagsSetsOnlyPassedFields(t *testing.T) {
+	fs := newConfigFlagSet()
+	flags := BindConfigFlags(fs, "log")
+	err := fs.Parse([]string{
+		"-log.level=debug,db=warn",
+		"-log.outputs", "console, split",
+		"-log.add-caller=false",
+		"-log.development",
+		"-log.file-name=",
+		"-log.sampling.initial=10",
+		"-log.sampling.levels=error=100:0",
+	})
+	if err != nil {
+		t.Fatalf("Parse 失敗：%v", err)
+	}
+
+	patch, err := flags.Patch()
+	if err != nil {
+		t.Fatalf("Patch 失敗：%v", err)
+	}
+	if patch.Format != nil || patch.LogPath != nil || patch.AddStacktrace != nil || patch.ColorEnabled != nil {
+		t.Fatalf("patch = %+v，未傳入的欄位應為 nil", patch)
+	}
+	if patch.FileName == nil || *patch.FileName != "" {
+		t.Fatalf("FileName = %v，預期明確空字串", patch.FileName)
+	}
+
+	cfg, err := flags.Resolve()
+	if err != nil {
+		t.Fatalf("Resolve 失敗：%v", err)
+	}
+	if cfg.Level != "debug,db=warn" || cfg.AddCaller || !cfg.Development || cfg.Format != "console" {
+		t.Fatalf("Config = %+v", cfg)
+	}
+	if !slices.Equal(cfg.Outputs, []string{"console", "split"}) {
+		t.Fatalf("Outputs = %v", cfg.Outputs)
+	}
+	if cfg.Sampling == nil || cfg.Sampling.Initial != 10 || cfg.Sampling.Levels["error"] != (SamplingLevel{Initial: 100}) {
+		t.Fatalf("Sampling = %+v", cfg.Sampling)
+	}
+
+	*patch.Outputs = nil
+	if again, _ := flags.Patch(); len(*again.Outputs) != 2 {
+		t.Fatal("Patch 應回傳獨立副本")
+	}
+}
+
+func TestBindConfigFlagsWithoutPrefix(t *testing.T) {
+	fs := newConfigFlagSet()
+	flags := BindConfigFlags(fs, "")
+	if err := fs.Parse([]string{"-format=json"}); err != nil {
+		t.Fatalf("Parse 失敗：%v", err)
+	}
+	patch, err := flags.Patch()
+	if err != nil || *patch != (ConfigPatch{Format: patch.Format}) || *patch.Format != "json" {
+		t.Fatalf("patch = %+v, 錯誤 = %v", patch, err)
+	}
+}
+
+func TestBindConfigFlagsRejectsInvalidValues(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "bool 使用 yes", args: []string{"-log.add-caller=yes"}},
+		{name: "outputs 含空白元素", args: []string{"-log.outputs=console,"}},
+		{name: "未知級別", args: []string{"-log.level=verbose"}},
+		{name: "整數無法解析", args: []string{"-log.sampling.initial=ten"}},
+		{name: "tick 無法解析", args: []string{"-log.sampling.tick=每秒"}},
+		{name: "級別覆寫格式錯誤", args: []string{"-log.sampling.levels=error"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := newConfigFlagSet()
+			BindConfigFlags(fs, "log")
+			err := fs.Parse(tt.args)
+			if err == nil || !strings.Contains(err.Error(), strings.SplitN(tt.args[0], "=", 2)[0]) {
+				t.Fatalf("錯誤 = %v，預期指出 flag 名稱", err)
+			}
+		})
+	}
+}
+
+func TestConfigFlagsValidatesThroughResolve(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "未知 output", args: []string{"-log.outputs=syslog"}, want: "syslog"},
+		{name: "缺少 sampling.initial", args: []string{"-log.sampling.thereafter=5"}, want: "-log.sampling.initial"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := newConfigFlagSet()
+			flags := BindConfigFlags(fs, "log.")
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatalf("Parse 失敗：%v", err)
+			}
+			cfg, err := flags.Resolve()
+			if cfg != nil || !errors.Is(err, ErrInvalidConfig) || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("回傳 %+v, %v，預期包含 %q 的 ErrInvalidConfig", cfg, err, tt.want)
+			}
+		})
+	}
+}
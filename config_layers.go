@@ -0,0 +1,65 @@
+package zlogger
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// DefaultLayer 是 ResolvedConfig.Sources 中代表 DefaultConfig 的來源名稱。
+const DefaultLayer = "default"
+
+// ConfigLayer 是具名的部分設定，例如 file、env 或 flags。Patch 可為 nil。
+type ConfigLayer struct {
+	Name  string
+	Patch *ConfigPatch
+}
+
+// ResolvedConfig 是依序合併後的完整設定與每個欄位的來源。
+//
+// Sources 以 ConfigPatch 的 json key（例如 level、outputs、sampling）對應最後設定該
+// 欄位的 layer 名稱；未被任何 layer 設定的欄位為 DefaultLayer。
+type ResolvedConfig struct {
+	Config  *Config           `json:"config"`
+	Sources map[string]string `json:"sources"`
+}
+
+// ResolveConfigLayers 依參數順序合併 layers，後面的 layer 覆寫前面的 layer，再以
+// ConfigPatch.Resolve 補齊預設值並驗證。
+//
+// 合併以欄位為單位：sampling 由最後設定它的 layer 整體提供，不與其他 layer 的
+// sampling 逐項合併。layer 名稱不可為空或使用 DefaultLayer。
+func ResolveConfigLayers(layers ...ConfigLayer) (*ResolvedConfig, error) {
+	merged := &ConfigPatch{}
+	mergedValue := reflect.ValueOf(merged).Elem()
+	patchType := mergedValue.Type()
+
+	sources := make(map[string]string, patchType.NumField())
+	for index := range patchType.NumField() {
+		sources[jsonFieldKey(patchType.Field(index))] = DefaultLayer
+	}
+
+	for _, layer := range layers {
+		if layer.Name == "" || strings.EqualFold(layer.Name, DefaultLayer) {
+			return nil, fmt.Errorf("%w: ConfigLayer 名稱 %q 無效", ErrInvalidConfig, layer.Name)
+		}
+		if layer.Patch == nil {
+			continue
+		}
+		layerValue := reflect.ValueOf(layer.Patch).Elem()
+		for index := range patchType.NumField() {
+			field := layerValue.Field(index)
+			if field.IsNil() {
+				continue
+			}
+			mergedValue.Field(index).Set(field)
+			sources[jsonFieldKey(patchType.Field(index))] = layer.Name
+		}
+	}
+
+	cfg, err := merged.Resolve()
+	if err != nil {
+		return nil, err
+	}
+	return &ResolvedConfig{Config: cfg, Sources: sources}, nil
+}
@@ -0,0 +1,88 @@
+package zlogger
+
+import (
+	"errors"
+	"maps"
+	"reflect"
+	"testing"
+)
+
+func TestResolveConfigLayersAppliesInOrderWithSources(t *testing.T) {
+	fileLevel, envLevel, flagLevel := "info,db=debug", "warn", "error"
+	format := "json"
+	outputs := []string{"console", "file"}
+	addCaller := false
+
+	t.Setenv("APP_LOG_LEVEL", envLevel)
+	t.Setenv("APP_LOG_ADD_CALLER", "true")
+	envPatch, err := ConfigPatchFromEnv("APP_LOG")
+	if err != nil {
+		t.Fatalf("ConfigPatchFromEnv 失敗：%v", err)
+	}
+
+	resolved, err := ResolveConfigLayers(
+		ConfigLayer{Name: "file", Patch: &ConfigPatch{Level: &fileLevel, Format: &format, Outputs: &outputs}},
+		ConfigLayer{Name: "env", Patch: envPatch},
+		ConfigLayer{Name: "empty"},
+		ConfigLayer{Name: "flags", Patch: &ConfigPatch{Level: &flagLevel, AddCaller: &addCaller}},
+	)
+	if err != nil {
+		t.Fatalf("ResolveConfigLayers 失敗：%v", err)
+	}
+
+	cfg := resolved.Config
+	if cfg.Level != "error" || cfg.Format != "json" || cfg.AddCaller || !reflect.DeepEqual(cfg.Outputs, outputs) {
+		t.Fatalf("Config = %+v", cfg)
+	}
+	want := map[string]string{
+		"level": "flags", "format": "file", "outputs": "file", "log_path": DefaultLayer,
+		"file_name": DefaultLayer, "split_prefix": DefaultLayer, "split_format": DefaultLayer,
+		"add_caller": "flags", "add_stacktrace": DefaultLayer, "development": DefaultLayer,
+		"color_enabled": DefaultLayer, "sampling": DefaultLayer,
+	}
+	if !maps.Equal(resolved.Sources, want) {
+		t.Fatalf("Sources = %v，預期 %v", resolved.Sources, want)
+	}
+
+	outputs[0] = "split"
+	if cfg.Outputs[0] != "console" {
+		t.Fatal("Config.Outputs 不應與 layer 共用 slice")
+	}
+}
+
+func TestResolveConfigLayersReplacesSamplingAsAWhole(t *testing.T) {
+	resolved, err := ResolveConfigLayers(
+		ConfigLayer{Name: "file", Patch: &ConfigPatch{Sampling: &SamplingConfig{Initial: 10, Tick: "5s"}}},
+		ConfigLayer{Name: "env", Patch: &ConfigPatch{Sampling: &SamplingConfig{Initial: 20}}},
+	)
+	if err != nil {
+		t.Fatalf("ResolveConfigLayers 失敗：%v", err)
+	}
+	if sampling := resolved.Config.Sampling; sampling.Initial != 20 || sampling.Tick != "" {
+		t.Fatalf("Sampling = %+v，預期由 env 整體提供", sampling)
+	}
+	if resolved.Sources["sampling"] != "env" {
+		t.Fatalf("sampling 來源 = %q", resolved.Sources["sampling"])
+	}
+}
+
+func TestResolveConfigLayersRejectsInvalidInput(t *testing.T) {
+	level := "verbose"
+	tests := []struct {
+		name  string
+		layer ConfigLayer
+	}{
+		{name: "空白名稱", layer: ConfigLayer{}},
+		{name: "保留名稱", layer: ConfigLayer{Name: "Default"}},
+		{name: "驗證失敗", layer: ConfigLayer{Name: "flags", Patch: &ConfigPatch{Level: &level}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved, err := ResolveConfigLayers(tt.layer)
+			if resolved != nil || !errors.Is(err, ErrInvalidConfig) {
+				t.Fatalf("回傳 %+v, %v，預期 ErrInvalidConfig", resolved, err)
+			}
+		})
+	}
+}
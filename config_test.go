@@ -257,6 +257,35 @@ func TestConfigValidateRejectsInvalidValues(t *testing.T) {
 			name: "file 的 LogPath 空白",
 			cfg:  &Config{Level: "info", Format: "console", Outputs: []string{"file"}, LogPath: ""},
 		},
+		{
+			name: "split 的 LogPath 空白",
+			cfg:  &Config{Level: "info", Format: "console", Outputs: []string{"split"}, SplitPrefix: "app"},
+		},
+		{
+			name: "split 的 SplitPrefix 空白",
+			cfg:  &Config{Level: "info", Format: "console", Outputs: []string{"split"}, LogPath: "./logs"},
+		},
+		{
+			name: "split 的 SplitPrefix 含路徑",
+			cfg: &Config{
+				Level:       "info",
+				Format:      "console",
+				Outputs:     []string{"split"},
+				LogPath:     "./logs",
+				SplitPrefix: "../app",
+			},
+		},
+		{
+			name: "未知 SplitFormat",
+			cfg: &Config{
+				Level:       "info",
+				Format:      "console",
+				Outputs:     []string{"split"},
+				LogPath:     "./logs",
+				SplitPrefix: "app",
+				SplitFormat: "xml",
+			},
+		},
 	}
 
 	for _, tt := range tests {
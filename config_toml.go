@@ -0,0 +1,458 @@
+package zlogger
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// parseTOMLConfigPatch 解析 TOML 子集，檢查 key 後轉為 JSON 交給 decodeConfigPatch。
+func parseTOMLConfigPatch(file string, data []byte) (*ConfigPatch, error) {
+	parser := &tomlParser{
+		file:   file,
+		root:   make(map[string]any),
+		lines:  make(configKeyLines),
+		tables: make(map[string]struct{}),
+	}
+	for index, text := range strings.Split(string(data), "\n") {
+		parser.line = index + 1
+		if err := parser.parseLine(strings.TrimSuffix(text, "\r")); err != nil {
+			return nil, err
+		}
+	}
+	if err := parser.checkKeys(parser.root, reflect.TypeFor[ConfigPatch](), ""); err != nil {
+		return nil, err
+	}
+
+	encoded, err := json.Marshal(parser.root)
+	if err != nil {
+		return nil, fmt.Errorf("%w: 設定檔 %s: %w", ErrInvalidConfig, file, err)
+	}
+	return decodeConfigPatch(file, encoded, parser.lines)
+}
+
+type tomlParser struct {
+	file   string
+	line   int
+	root   map[string]any
+	lines  configKeyLines
+	tables map[string]struct{}
+	table  []string
+}
+
+func (p *tomlParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w: 設定檔 %s 第 %d 行: %s", ErrInvalidConfig, p.file, p.line, fmt.Sprintf(format, args...))
+}
+
+func (p *tomlParser) parseLine(text string) error {
+	cursor := &tomlCursor{text: text}
+	cursor.skipSpace()
+	if cursor.atEnd() {
+		return nil
+	}
+
+	if cursor.peek() == '[' {
+		if strings.HasPrefix(cursor.rest(), "[[") {
+			return p.errorf("不支援 array of tables")
+		}
+		cursor.pos++
+		keys, err := cursor.key()
+		if err != nil {
+			return p.errorf("%v", err)
+		}
+		if !cursor.consume(']') || !cursor.atEnd() {
+			return p.errorf("table 標頭格式錯誤")
+		}
+		return p.defineTable(keys)
+	}
+
+	keys, err := cursor.key()
+	if err != nil {
+		return p.errorf("%v", err)
+	}
+	if !cursor.consume('=') {
+		return p.errorf("key 後必須是 =")
+	}
+	value, err := cursor.value()
+	if err != nil {
+		return p.errorf("%v", err)
+	}
+	if !cursor.atEnd() {
+		return p.errorf("值之後有多餘內容 %q", cursor.rest())
+	}
+	return p.assign(append(slices.Clone(p.table), keys...), value)
+}
+
+func (p *tomlParser) defineTable(keys []string) error {
+	path := strings.Join(keys, ".")
+	if _, exists := p.tables[path]; exists {
+		return p.errorf("table %q 重複定義", path)
+	}
+	if _, err := p.descend(keys); err != nil {
+		return err
+	}
+	p.tables[path] = struct{}{}
+	if _, exists := p.lines[path]; !exists {
+		p.lines[path] = p.line
+	}
+	p.table = keys
+	return nil
+}
+
+// descend 回傳 keys 指向的 table，必要時建立中間 table。
+func (p *tomlParser) descend(keys []string) (map[string]any, error) {
+	current := p.root
+	for index, key := range keys {
+		next, exists := current[key]
+		if !exists {
+			child := make(map[string]any)
+			current[key] = child
+			p.lines[strings.Join(keys[:index+1], ".")] = p.line
+			current = child
+			continue
+		}
+		child, ok := next.(map[string]any)
+		if !ok {
+			return nil, p.errorf("key %q 已定義為值", strings.Join(keys[:index+1], "."))
+		}
+		current = child
+	}
+	return current, nil
+}
+
+func (p *tomlParser) assign(keys []string, value any) error {
+	parent, err := p.descend(keys[:len(keys)-1])
+	if err != nil {
+		return err
+	}
+	path := strings.Join(keys, ".")
+	last := keys[len(keys)-1]
+	if _, exists := parent[last]; exists {
+		return p.errorf("key %q 重複", path)
+	}
+	parent[last] = value
+	p.recordLines(path, value)
+	return nil
+}
+
+// recordLines 記錄 inline table 內各 key 的行號。
+func (p *tomlParser) recordLines(path string, value any) {
+	p.lines[path] = p.line
+	if table, ok := value.(map[string]any); ok {
+		for key, child := range table {
+			p.recordLines(path+"."+key, child)
+		}
+	}
+}
+
+// checkKeys 依 ConfigPatch 的 json tag 檢查所有 key，依行號回報第一個未知 key。
+func (p *tomlParser) checkKeys(table map[string]any, t reflect.Type, path string) error {
+	keys := make([]string, 0, len(table))
+	for key := range table {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b string) int {
+		if lineA, lineB := p.lines[joinConfigPath(path, a)], p.lines[joinConfigPath(path, b)]; lineA != lineB {
+			return lineA - lineB
+		}
+		return strings.Compare(a, b)
+	})
+
+	for _, key := range keys {
+		child := joinConfigPath(path, key)
+		childType, known := configChildType(t, key)
+		if !known {
+			return fmt.Errorf("%w: 設定檔 %s 第 %d 行: 未知的 key %q", ErrInvalidConfig, p.file, p.lines[child], child)
+		}
+		if nested, ok := table[key].(map[string]any); ok {
+			if err := p.checkKeys(nested, childType, child); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// tomlCursor 逐字元讀取單行 TOML。
+type tomlCursor struct {
+	text string
+	pos  int
+}
+
+func (c *tomlCursor) rest() string {
+	return c.text[c.pos:]
+}
+
+func (c *tomlCursor) peek() byte {
+	if c.pos >= len(c.text) {
+		return 0
+	}
+	return c.text[c.pos]
+}
+
+func (c *tomlCursor) skipSpace() {
+	for c.pos < len(c.text) && (c.text[c.pos] == ' ' || c.text[c.pos] == '\t') {
+		c.pos++
+	}
+}
+
+// atEnd 回報剩餘內容是否只有空白或註解。
+func (c *tomlCursor) atEnd() bool {
+	c.skipSpace()
+	return c.pos >= len(c.text) || c.text[c.pos] == '#'
+}
+
+// consume 略過空白後讀取指定字元。
+func (c *tomlCursor) consume(want byte) bool {
+	c.skipSpace()
+	if c.peek() != want {
+		return false
+	}
+	c.pos++
+	return true
+}
+
+// key 讀取以 "." 分隔的 bare 或 quoted key。
+func (c *tomlCursor) key() ([]string, error) {
+	var keys []string
+	for {
+		c.skipSpace()
+		var key string
+		switch c.peek() {
+		case '"', '\'':
+			quoted, err := c.string()
+			if err != nil {
+				return nil, err
+			}
+			key = quoted
+		default:
+			start := c.pos
+			for c.pos < len(c.text) && isTOMLBareKeyChar(c.text[c.pos]) {
+				c.pos++
+			}
+			if start == c.pos {
+				return nil, fmt.Errorf("缺少 key")
+			}
+			key = c.text[start:c.pos]
+		}
+		keys = append(keys, key)
+		if !c.consume('.') {
+			return keys, nil
+		}
+	}
+}
+
+func isTOMLBareKeyChar(char byte) bool {
+	return char >= 'a' && char <= 'z' || char >= 'A' && char <= 'Z' || char >= '0' && char <= '9' ||
+		char == '_' || char == '-'
+}
+
+func (c *tomlCursor) value() (any, error) {
+	c.skipSpace()
+	switch char := c.peek(); {
+	case char == '"' || char == '\'':
+		return c.string()
+	case char == '[':
+		return c.array()
+	case char == '{':
+		return c.inlineTable()
+	case strings.HasPrefix(c.rest(), "true"):
+		c.pos += len("true")
+		return true, nil
+	case strings.HasPrefix(c.rest(), "false"):
+		c.pos += len("false")
+		return false, nil
+	case char == '+' || char == '-' || char >= '0' && char <= '9':
+		return c.integer()
+	default:
+		return nil, fmt.Errorf("無法解析的值 %q", c.rest())
+	}
+}
+
+func (c *tomlCursor) string() (string, error) {
+	quote := c.text[c.pos]
+	if strings.HasPrefix(c.rest(), strings.Repeat(string(quote), 3)) {
+		return "", fmt.Errorf("不支援多行字串")
+	}
+	end := c.pos + 1
+	for end < len(c.text) && c.text[end] != quote {
+		if quote == '"' && c.text[end] == '\\' {
+			end++
+		}
+		end++
+	}
+	if end >= len(c.text) {
+		return "", fmt.Errorf("字串缺少結尾引號")
+	}
+	raw := c.text[c.pos : end+1]
+	c.pos = end + 1
+	if quote == '\'' {
+		return raw[1 : len(raw)-1], nil
+	}
+	value, ok := unescapeTOMLString(raw[1 : len(raw)-1])
+	if !ok {
+		return "", fmt.Errorf("字串 %s 的跳脫字元無效", raw)
+	}
+	return value, nil
+}
+
+// unescapeTOMLString 依 TOML 規則解碼 basic string 內容，只接受 \b \t \n \f \r \e \" \\
+// 與 \uXXXX、\UXXXXXXXX。
+func unescapeTOMLString(body string) (string, bool) {
+	if !strings.Contains(body, `\`) {
+		return body, true
+	}
+	var value strings.Builder
+	for index := 0; index < len(body); index++ {
+		if body[index] != '\\' {
+			value.WriteByte(body[index])
+			continue
+		}
+		index++
+		if index >= len(body) {
+			return "", false
+		}
+		switch escape := body[index]; escape {
+		case 'b':
+			value.WriteByte('\b')
+		case 't':
+			value.WriteByte('\t')
+		case 'n':
+			value.WriteByte('\n')
+		case 'f':
+			value.WriteByte('\f')
+		case 'r':
+			value.WriteByte('\r')
+		case 'e':
+			value.WriteByte('\x1b')
+		case '"', '\\':
+			value.WriteByte(escape)
+		case 'u', 'U':
+			size := 4
+			if escape == 'U' {
+				size = 8
+			}
+			if index+size >= len(body) {
+				return "", false
+			}
+			hex := body[index+1 : index+1+size]
+			code, err := strconv.ParseUint(hex, 16, 32)
+			if err != nil || !utf8.ValidRune(rune(code)) {
+				return "", false
+			}
+			value.WriteRune(rune(code))
+			index += size
+		default:
+			return "", false
+		}
+	}
+	return value.String(), true
+}
+
+func (c *tomlCursor) integer() (int64, error) {
+	start := c.pos
+	for c.pos < len(c.text) && strings.IndexByte("+-0123456789_", c.text[c.pos]) >= 0 {
+		c.pos++
+	}
+	text := c.text[start:c.pos]
+	if strings.IndexByte(".eE:", c.peek()) >= 0 {
+		return 0, fmt.Errorf("只支援整數，不支援 %q", text+c.rest())
+	}
+	if !isTOMLDecimal(text) {
+		return 0, fmt.Errorf("整數 %q 無效", text)
+	}
+	value, err := strconv.ParseInt(strings.ReplaceAll(text, "_", ""), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("整數 %q 無效", text)
+	}
+	return value, nil
+}
+
+// isTOMLDecimal 回報 text 是否為 TOML 十進位整數：可有一個正負號，0 以外不可以 0 開頭，
+// 底線必須位於兩個數字之間。
+func isTOMLDecimal(text string) bool {
+	if strings.HasPrefix(text, "+") || strings.HasPrefix(text, "-") {
+		text = text[1:]
+	}
+	if text == "" || text[0] == '0' && len(text) > 1 {
+		return false
+	}
+	for index := range len(text) {
+		if text[index] == '_' {
+			if index == 0 || index == len(text)-1 || !isASCIIDigit(text[index-1]) || !isASCIIDigit(text[index+1]) {
+				return false
+			}
+			continue
+		}
+		if !isASCIIDigit(text[index]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isASCIIDigit(char byte) bool {
+	return char >= '0' && char <= '9'
+}
+
+func (c *tomlCursor) array() ([]any, error) {
+	c.pos++
+	values := []any{}
+	for {
+		if c.consume(']') {
+			return values, nil
+		}
+		if c.atEnd() {
+			return nil, fmt.Errorf("array 必須在同一行結束")
+		}
+		value, err := c.value()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		if !c.consume(',') {
+			if !c.consume(']') {
+				return nil, fmt.Errorf("array 元素之間必須以逗號分隔")
+			}
+			return values, nil
+		}
+	}
+}
+
+func (c *tomlCursor) inlineTable() (map[string]any, error) {
+	c.pos++
+	table := make(map[string]any)
+	if c.consume('}') {
+		return table, nil
+	}
+	for {
+		keys, err := c.key()
+		if err != nil {
+			return nil, err
+		}
+		if len(keys) != 1 {
+			return nil, fmt.Errorf("inline table 不支援 dotted key %q", strings.Join(keys, "."))
+		}
+		if !c.consume('=') {
+			return nil, fmt.Errorf("key 後必須是 =")
+		}
+		value, err := c.value()
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := table[keys[0]]; exists {
+			return nil, fmt.Errorf("inline table 的 key %q 重複", keys[0])
+		}
+		table[keys[0]] = value
+		if c.consume('}') {
+			return table, nil
+		}
+		if !c.consume(',') {
+			return nil, fmt.Errorf("inline table 的項目之間必須以逗號分隔")
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.uber.org/zap"
@@ -17,9 +18,13 @@ import (
 var (
 	// ErrAlreadyConfigured 表示全域 logger 已完成一次成功設定。
 	ErrAlreadyConfigured = errors.New("全域 logger 已完成設定")
+	// ErrNotConfigured 表示全域 logger 尚未透過 Configure 或 Init 設定。
+	ErrNotConfigured = errors.New("全域 logger 尚未設定")
 
 	globalLogger   *zap.Logger
+	globalInstance *Instance
 	zapGlobalLevel = zap.NewAtomicLevel()
+	globalLevels   *nameLevels
 	globalConfig   *Config
 
 	configureMu   sync.Mutex
@@ -32,11 +37,20 @@ type Field = zap.Field
 
 // Instance 持有非全域 logger 與其擁有的資源。
 type Instance struct {
-	logger  *zap.Logger
-	level   zap.AtomicLevel
-	closers []io.Closer
-
-	mu        sync.RWMutex
+	logger   *zap.Logger
+	levels   *nameLevels
+	core     *swapCore
+	settings fileOutputSettings
+	sampled  *atomic.Uint64
+	dropped  *atomic.Uint64
+
+	// reloadMu 序列化 Reload，並保護 config。
+	reloadMu sync.Mutex
+	config   *Config
+
+	mu sync.RWMutex
+	// closers 是目前 core 擁有的資源，Reload 替換 core 時一併更新。
+	closers   []io.Closer
 	closed    bool
 	closeOnce sync.Once
 	closeErr  error
@@ -65,7 +79,26 @@ func (i *Instance) Sync() error {
 	return i.logger.Sync()
 }
 
+// InstanceStats 是 Instance 的累計執行統計。
+type InstanceStats struct {
+	// DroppedEntries 是 WithAsync 佇列已滿時依 OverflowPolicy 丟棄的日誌筆數。
+	DroppedEntries uint64
+	// SampledEntries 是 Config.Sampling 取樣丟棄的日誌筆數。
+	SampledEntries uint64
+}
+
+// Stats 回傳目前的累計統計；Close 後仍可呼叫。
+func (i *Instance) Stats() InstanceStats {
+	if i == nil {
+		return InstanceStats{}
+	}
+	return InstanceStats{DroppedEntries: i.dropped.Load(), SampledEntries: i.sampled.Load()}
+}
+
 // Close 關閉 Instance 擁有的資源，且可安全重複及並行呼叫。
+//
+// 啟用 WithAsync 時會先寫完已排入佇列的日誌，再關閉檔案。進行中的寫入超過 1 秒仍未完成時
+// 照常關閉，回傳的錯誤包含等待逾時。
 func (i *Instance) Close() error {
 	if i == nil {
 		return nil
@@ -74,10 +107,11 @@ func (i *Instance) Close() error {
 	i.closeOnce.Do(func() {
 		i.mu.Lock()
 		i.closed = true
-		closers := slices.Clone(i.closers)
+		generation := i.core.current.Load()
 		i.mu.Unlock()
 
-		i.closeErr = closeOwnedResources(closers, "關閉 logger 資源")
+		i.levels.stopRevert()
+		i.closeErr = generation.retire("關閉 logger 資源")
 	})
 
 	return i.closeErr
@@ -106,32 +140,26 @@ func NewWithOptions(cfg *Config, opts ...FileOutputOption) (*Instance, error) {
 	if err != nil {
 		return nil, err
 	}
+	return newInstance(cfg, settings)
+}
 
-	level := zap.NewAtomicLevelAt(parseLevel(cfg.Level))
-	encoderConfig := buildEncoderConfig(cfg)
-	cores := make([]zapcore.Core, 0, len(cfg.Outputs))
-	closers := make([]io.Closer, 0, 1)
-
-	rollback := func(buildErr error) error {
-		return errors.Join(buildErr, closeOwnedResources(closers, "回收 logger 資源"))
+// newInstance 依已驗證的 cfg 與解析後的 settings 建立 Instance。
+func newInstance(cfg *Config, settings fileOutputSettings) (*Instance, error) {
+	spec, err := parseLevelSpec(cfg.Level)
+	if err != nil {
+		return nil, err
 	}
-
-	for _, output := range cfg.Outputs {
-		switch output {
-		case "console":
-			cores = append(cores, newConsoleCore(cfg, encoderConfig, level))
-		case "file":
-			core, file, err := newFileCoreWithSettings(cfg, encoderConfig, level, settings)
-			if err != nil {
-				return nil, rollback(err)
-			}
-			closers = append(closers, file)
-			cores = append(cores, core)
-		}
+	levels := newNameLevels(spec)
+	sampled, dropped := new(atomic.Uint64), new(atomic.Uint64)
+	generation, err := newCoreGeneration(cfg, settings, levels, sampled, dropped, nil)
+	if err != nil {
+		return nil, err
 	}
 
-	logger := zap.New(zapcore.NewTee(cores...))
-	options := make([]zap.Option, 0, 3)
+	// Reload 替換 swap 內的 generation；名稱級別最先檢查，未啟用的日誌不計入取樣。
+	swap := newSwapCore(generation)
+	logger := zap.New(&namedLevelCore{Core: swap, levels: levels})
+	options := []zap.Option{zap.ErrorOutput(settings.errorOutput())}
 	if cfg.AddCaller {
 		options = append(options, zap.AddCaller(), zap.AddCallerSkip(1))
 	}
@@ -141,14 +169,17 @@ func NewWithOptions(cfg *Config, opts ...FileOutputOption) (*Instance, error) {
 	if cfg.Development {
 		options = append(options, zap.Development())
 	}
-	if len(options) > 0 {
-		logger = logger.WithOptions(options...)
-	}
+	logger = logger.WithOptions(options...)
 
 	instance := &Instance{
-		logger:  logger,
-		level:   level,
-		closers: closers,
+		logger:   logger,
+		levels:   levels,
+		core:     swap,
+		settings: settings,
+		sampled:  sampled,
+		dropped:  dropped,
+		config:   cfg,
+		closers:  generation.closers,
 	}
 	instance.logger.Info("logger initialized",
 		zap.String("level", cfg.Level),
@@ -217,13 +248,17 @@ func configureResolvedWithOptions(
 	}
 
 	previousLogger := globalLogger
+	previousInstance := globalInstance
 	previousConfig := globalConfig
 	previousLevel := zapGlobalLevel
+	previousLevels := globalLevels
 	restoreZapGlobals := zap.ReplaceGlobals(instance.logger)
 
 	globalLogger = instance.logger
+	globalInstance = instance
 	globalConfig = cfg.normalizedCopy()
-	zapGlobalLevel = instance.level
+	zapGlobalLevel = instance.levels.base
+	globalLevels = instance.levels
 	configured = true
 
 	var cleanupOnce sync.Once
@@ -232,8 +267,10 @@ func configureResolvedWithOptions(
 		cleanupOnce.Do(func() {
 			configureMu.Lock()
 			globalLogger = previousLogger
+			globalInstance = previousInstance
 			globalConfig = previousConfig
 			zapGlobalLevel = previousLevel
+			globalLevels = previousLevels
 			restoreZapGlobals()
 			configureMu.Unlock()
 
@@ -271,7 +308,7 @@ func buildEncoderConfig(cfg *Config) zapcore.EncoderConfig {
 	}
 }
 
-func newConsoleCore(cfg *Config, encoderConfig zapcore.EncoderConfig, level zap.AtomicLevel) zapcore.Core {
+func newConsoleCore(cfg *Config, encoderConfig zapcore.EncoderConfig, level zapcore.LevelEnabler) zapcore.Core {
 	encoder := newEncoder(cfg.Format, encoderConfig)
 	return zapcore.NewCore(encoder, zapcore.Lock(os.Stdout), level)
 }
@@ -279,8 +316,8 @@ func newConsoleCore(cfg *Config, encoderConfig zapcore.EncoderConfig, level zap.
 func newFileCore(
 	cfg *Config,
 	encoderConfig zapcore.EncoderConfig,
-	level zap.AtomicLevel,
-) (zapcore.Core, *os.File, error) {
+	level zapcore.LevelEnabler,
+) (zapcore.Core, io.Closer, error) {
 	settings, err := resolveFileOutputOptions()
 	if err != nil {
 		return nil, nil, err
@@ -288,34 +325,127 @@ func newFileCore(
 	return newFileCoreWithSettings(cfg, encoderConfig, level, settings)
 }
 
+// newFileCoreWithSettings 建立 file output。
+//
+// FileName 為空時依換檔週期切換日期檔名；指定 FileName 時固定寫入該檔案。
 func newFileCoreWithSettings(
 	cfg *Config,
 	encoderConfig zapcore.EncoderConfig,
-	level zap.AtomicLevel,
+	level zapcore.LevelEnabler,
 	settings fileOutputSettings,
-) (zapcore.Core, *os.File, error) {
+) (zapcore.Core, io.Closer, error) {
+	file, err := openFileOutput(cfg, settings)
+	if err != nil {
+		return nil, nil, err
+	}
+	return fileOutputCore(cfg, encoderConfig, level, settings, file), file, nil
+}
+
+// openFileOutput 開啟 file output 的資源：FileName 為空時為換檔的 SplitOutput，否則為固定檔案。
+func openFileOutput(cfg *Config, settings fileOutputSettings) (io.Closer, error) {
+	if cfg.FileName == "" {
+		fileOut, err := openRotatingFileOutput(cfg, settings, settings.outputClock())
+		if err != nil {
+			return nil, err
+		}
+		return fileOut, nil
+	}
 	if err := os.MkdirAll(cfg.LogPath, settings.dirPerm); err != nil {
-		return nil, nil, fmt.Errorf("建立日誌目錄 %q: %w", cfg.LogPath, err)
+		return nil, fmt.Errorf("建立日誌目錄 %q: %w", cfg.LogPath, err)
+	}
+	logFile, err := openFixedLogFile(cfg.LogPath, cfg.FileName, settings)
+	if err != nil {
+		return nil, err
 	}
+	return logFile, nil
+}
 
-	logFileName := cfg.FileName
-	if logFileName == "" {
-		logFileName = time.Now().Format("2006-01-02") + ".log"
+// fileOutputCore 以 openFileOutput 開啟的資源建立 file output 的 core。
+func fileOutputCore(
+	cfg *Config,
+	encoderConfig zapcore.EncoderConfig,
+	level zapcore.LevelEnabler,
+	settings fileOutputSettings,
+	file io.Closer,
+) zapcore.Core {
+	encoder := newEncoder(cfg.Format, encoderConfig)
+	if fileOut, ok := file.(*SplitOutput); ok {
+		return fileOut.core(encoder, level)
 	}
-	logFiles, err := openRootedLogFilesWithPermissions(cfg.LogPath, settings.filePerm, logFileName)
+	//nolint:forcetypeassert // openFileOutput 只回傳 *SplitOutput 或 *fixedLogFile。
+	logFile := file.(*fixedLogFile)
+	guard := newDiskSpaceGuard(cfg.LogPath, settings, time.Now)
+	return withDiskSpaceGuard(zapcore.NewCore(encoder, logFile, level), guard)
+}
+
+// openSplitOutput 開啟 split output 使用的 SplitOutput。
+func openSplitOutput(cfg *Config, settings fileOutputSettings) (*SplitOutput, error) {
+	return newSplitOutputWithSettings(
+		cfg.LogPath,
+		cfg.SplitPrefix,
+		settings.outputClock(),
+		openSplitFilesWithPermissions,
+		settings,
+	)
+}
+
+// splitOutputCore 以 splitOut 建立 split output 的 core；SplitFormat 為空時沿用 Format。
+func splitOutputCore(
+	cfg *Config,
+	encoderConfig zapcore.EncoderConfig,
+	level zapcore.LevelEnabler,
+	splitOut *SplitOutput,
+) zapcore.Core {
+	format := cfg.SplitFormat
+	if format == "" {
+		format = cfg.Format
+	}
+	// 檔案不輸出 ANSI 色碼，即使 console 輸出啟用顏色。
+	fileEncoderConfig := encoderConfig
+	fileEncoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+	return splitOut.core(newEncoder(format, fileEncoderConfig), level)
+}
+
+// newRotatingFileCore 以單一 route 的 SplitOutput 實作 file output 的日期換檔。
+//
+// 檔名樣板預設為 {date}.log；WithSplitRoutes 只影響 split output，不套用於此。
+func newRotatingFileCore(
+	cfg *Config,
+	encoderConfig zapcore.EncoderConfig,
+	level zapcore.LevelEnabler,
+	settings fileOutputSettings,
+	clock rotationClock,
+) (zapcore.Core, *SplitOutput, error) {
+	fileOut, err := openRotatingFileOutput(cfg, settings, clock)
 	if err != nil {
 		return nil, nil, err
 	}
-	if len(logFiles) != 1 {
-		return nil, nil, errors.Join(
-			fmt.Errorf("取得日誌檔案數量 %d，預期 1: %w", len(logFiles), os.ErrInvalid),
-			closeRootedLogFiles(logFiles),
-		)
+	return fileOut.core(newEncoder(cfg.Format, encoderConfig), level), fileOut, nil
+}
+
+// openRotatingFileOutput 開啟 newRotatingFileCore 使用的單一 route SplitOutput。
+func openRotatingFileOutput(cfg *Config, settings fileOutputSettings, clock rotationClock) (*SplitOutput, error) {
+	fileName, err := settings.fileNameTemplate(defaultFileNameTemplate)
+	if err != nil {
+		return nil, err
+	}
+	if err := fileName.validateSingle(); err != nil {
+		return nil, err
+	}
+	settings.fileName = fileName
+	currentLink, err := settings.currentLinkTemplate(defaultCurrentLinkTemplate)
+	if err != nil {
+		return nil, err
+	}
+	if currentLink != nil {
+		if err := currentLink.validateSingle(); err != nil {
+			return nil, err
+		}
+		settings.currentLink = currentLink
 	}
-	logFile := logFiles[0]
+	settings.routes = []SplitRoute{{Name: "file", MinLevel: zapcore.DebugLevel, MaxLevel: zapcore.FatalLevel}}
 
-	encoder := newEncoder(cfg.Format, encoderConfig)
-	return zapcore.NewCore(encoder, zapcore.Lock(logFile), level), logFile, nil
+	return newSplitOutputWithSettings(cfg.LogPath, "", clock, openSplitFilesWithPermissions, settings)
 }
 
 func newEncoder(format string, encoderConfig zapcore.EncoderConfig) zapcore.Encoder {
@@ -348,8 +478,8 @@ func buildConsoleCore(encoderConfig zapcore.EncoderConfig) zapcore.Core {
 	return newConsoleCore(cfg, encoderConfig, zapGlobalLevel)
 }
 
-// buildFileCore 保留既有 package-private 測試入口，並回傳由呼叫端關閉的檔案。
-func buildFileCore(encoderConfig zapcore.EncoderConfig) (zapcore.Core, *os.File) {
+// buildFileCore 保留既有 package-private 測試入口，並回傳由呼叫端關閉的輸出。
+func buildFileCore(encoderConfig zapcore.EncoderConfig) (zapcore.Core, io.Closer) {
 	cfg := globalConfig
 	if cfg == nil {
 		cfg = DefaultConfig()
@@ -418,12 +548,49 @@ func Fatal(msg string, fields ...Field) {
 	}
 }
 
-// SetLevel 動態調整全域 logger level。
+// SetLevel 動態調整全域 logger level，保留名稱覆寫並取消 LevelHandler 尚未到期的恢復。
 func SetLevel(level string) {
-	zapGlobalLevel.SetLevel(parseLevel(level))
+	configureMu.Lock()
+	levels := globalLevels
+	configureMu.Unlock()
+	if levels != nil {
+		levels.setBase(parseLevel(level))
+	} else {
+		zapGlobalLevel.SetLevel(parseLevel(level))
+	}
 	Info("log level changed", String("level", level))
 }
 
+// SetLevelSpec 以 "info,db=debug" 格式整批替換全域 logger 的基礎級別與名稱覆寫。
+//
+// 與 SetLevel 不同，未知級別會回傳包裝 ErrInvalidConfig 的錯誤並保留目前設定；
+// 尚未設定全域 logger 時回傳 ErrNotConfigured。
+func SetLevelSpec(spec string) error {
+	parsed, err := parseLevelSpec(spec)
+	if err != nil {
+		return err
+	}
+	configureMu.Lock()
+	levels := globalLevels
+	configureMu.Unlock()
+	if levels == nil {
+		return ErrNotConfigured
+	}
+	levels.set(parsed)
+	return nil
+}
+
+// LevelSpec 回傳全域 logger 目前生效的級別規格；尚未設定時回傳空字串。
+func LevelSpec() string {
+	configureMu.Lock()
+	levels := globalLevels
+	configureMu.Unlock()
+	if levels == nil {
+		return ""
+	}
+	return levels.spec().String()
+}
+
 // GetLogger 回傳目前全域 zap logger。
 func GetLogger() *zap.Logger {
 	return globalLogger
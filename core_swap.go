@@ -0,0 +1,287 @@
+package zlogger
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// generationDrainTimeout 是替換 core 後等待舊 core 完成寫入的上限；
+// 只有呼叫端取得 CheckedEntry 卻未寫出時才會等滿。
+const generationDrainTimeout = time.Second
+
+// errGenerationDrainTimeout 表示等待進行中的寫入逾時，資源仍會關閉。
+var errGenerationDrainTimeout = errors.New("等待進行中的寫入逾時")
+
+// coreGeneration 是一組依 Config 建立的輸出、取樣與非同步佇列，以及其擁有的資源。
+//
+// inflight 計算已通過 Check 但尚未寫完的日誌；retire 先標記 retired，再等待 inflight
+// 歸零時關閉的 drained 後寫完佇列並關閉資源，確保替換期間不會寫入已關閉的檔案。
+type coreGeneration struct {
+	core    zapcore.Core
+	closers []io.Closer
+	// outputs 以 outputKey 索引 closers 中的檔案輸出，供下一個 generation 沿用。
+	outputs map[string]io.Closer
+	// inherited 是沿用自前一個 generation 的輸出，替換成功前仍由前一個 generation 擁有。
+	inherited []io.Closer
+	async     *asyncQueue
+	inflight  atomic.Int64
+	retired   atomic.Bool
+	drained   chan struct{}
+	drainOnce sync.Once
+	release   *generationRelease
+}
+
+// newCoreGeneration 依已驗證的 cfg 建立輸出；失敗時關閉已開啟的資源。
+//
+// previous 不為 nil 時，output 種類、目錄與檔名（split 為 prefix）相同的檔案輸出沿用
+// previous 的資源而不重新開啟，避免同一組檔案同時由兩個輸出各自換檔、清理與壓縮。
+func newCoreGeneration(
+	cfg *Config,
+	settings fileOutputSettings,
+	levels *nameLevels,
+	sampled *atomic.Uint64,
+	dropped *atomic.Uint64,
+	previous *coreGeneration,
+) (*coreGeneration, error) {
+	encoderConfig := buildEncoderConfig(cfg)
+	cores := make([]zapcore.Core, 0, len(cfg.Outputs))
+	closers := make([]io.Closer, 0, 1)
+	outputs := make(map[string]io.Closer, 1)
+	var inherited []io.Closer
+
+	rollback := func(buildErr error) error {
+		opened := withoutClosers(closers, inherited)
+		return errors.Join(buildErr, closeOwnedResources(opened, "回收 logger 資源"))
+	}
+	// resource 回傳 key 對應的既有輸出，沒有時以 open 開啟。
+	resource := func(key string, open func() (io.Closer, error)) (io.Closer, error) {
+		if previous != nil {
+			if closer, ok := previous.outputs[key]; ok {
+				inherited = append(inherited, closer)
+				closers = append(closers, closer)
+				outputs[key] = closer
+				return closer, nil
+			}
+		}
+		closer, err := open()
+		if err != nil {
+			return nil, err
+		}
+		closers = append(closers, closer)
+		outputs[key] = closer
+		return closer, nil
+	}
+
+	for _, output := range cfg.Outputs {
+		switch output {
+		case "console":
+			cores = append(cores, newConsoleCore(cfg, encoderConfig, levels))
+		case "file":
+			file, err := resource(outputKey(output, cfg.LogPath, cfg.FileName), func() (io.Closer, error) {
+				return openFileOutput(cfg, settings)
+			})
+			if err != nil {
+				return nil, rollback(err)
+			}
+			cores = append(cores, fileOutputCore(cfg, encoderConfig, levels, settings, file))
+		case "split":
+			splitOut, err := resource(outputKey(output, cfg.LogPath, cfg.SplitPrefix), func() (io.Closer, error) {
+				return openSplitOutput(cfg, settings)
+			})
+			if err != nil {
+				return nil, rollback(err)
+			}
+			//nolint:forcetypeassert // split output 的資源只由 openSplitOutput 建立。
+			cores = append(cores, splitOutputCore(cfg, encoderConfig, levels, splitOut.(*SplitOutput)))
+		}
+	}
+
+	generation := &coreGeneration{
+		closers:   closers,
+		outputs:   outputs,
+		inherited: inherited,
+		drained:   make(chan struct{}),
+	}
+	generation.release = &generationRelease{generation: generation}
+	core := zapcore.NewTee(cores...)
+	if settings.asyncQueueSize > 0 {
+		generation.async = newAsyncQueue(settings.asyncQueueSize, settings.overflow, dropped, settings.errorOutput())
+		core = newAsyncCore(core, generation.async)
+	}
+	// 取樣在排入非同步佇列前執行，被丟棄的日誌不占用佇列。
+	if cfg.Sampling != nil {
+		core = newSampledCore(core, cfg.Sampling, sampled)
+	}
+	generation.core = core
+	return generation, nil
+}
+
+// outputKey 識別一個檔案輸出，由 output 種類、絕對目錄與檔名或 prefix 組成。
+func outputKey(output, directory, leaf string) string {
+	if absolute, err := filepath.Abs(directory); err == nil {
+		directory = absolute
+	}
+	return output + "\x00" + directory + "\x00" + leaf
+}
+
+// disown 將 resources 自 closers 移除，改由沿用它們的 generation 關閉；須在 retire 前呼叫。
+func (g *coreGeneration) disown(resources []io.Closer) {
+	g.closers = withoutClosers(g.closers, resources)
+}
+
+// withoutClosers 回傳 closers 中不屬於 remove 的資源，不修改 closers。
+func withoutClosers(closers, remove []io.Closer) []io.Closer {
+	return slices.DeleteFunc(slices.Clone(closers), func(closer io.Closer) bool {
+		return slices.Contains(remove, closer)
+	})
+}
+
+// retire 等待進行中的寫入完成，寫完非同步佇列後關閉資源。
+//
+// 超過 generationDrainTimeout 仍有寫入未完成時，照常關閉資源並回傳包含逾時的錯誤。
+func (g *coreGeneration) retire(operation string) error {
+	g.retired.Store(true)
+	if g.inflight.Load() == 0 {
+		g.signalDrained()
+	}
+
+	var drainErr error
+	timer := time.NewTimer(generationDrainTimeout)
+	select {
+	case <-g.drained:
+		timer.Stop()
+	case <-timer.C:
+		drainErr = fmt.Errorf("%s: %w（%d 筆，上限 %s）",
+			operation, errGenerationDrainTimeout, g.inflight.Load(), generationDrainTimeout)
+	}
+	g.async.close()
+	return errors.Join(drainErr, closeOwnedResources(g.closers, operation))
+}
+
+// leave 解除一次占用；generation 已 retired 且沒有進行中的寫入時通知 retire。
+func (g *coreGeneration) leave() {
+	if g.inflight.Add(-1) == 0 && g.retired.Load() {
+		g.signalDrained()
+	}
+}
+
+func (g *coreGeneration) signalDrained() {
+	g.drainOnce.Do(func() { close(g.drained) })
+}
+
+// generationRelease 排在 CheckedEntry 的最後，於其他 core 寫完後解除 generation 的占用。
+type generationRelease struct {
+	generation *coreGeneration
+}
+
+func (r *generationRelease) Enabled(zapcore.Level) bool {
+	return true
+}
+
+func (r *generationRelease) With([]zapcore.Field) zapcore.Core {
+	return r
+}
+
+func (r *generationRelease) Check(_ zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return checked
+}
+
+func (r *generationRelease) Write(zapcore.Entry, []zapcore.Field) error {
+	r.generation.leave()
+	return nil
+}
+
+func (r *generationRelease) Sync() error {
+	return nil
+}
+
+// swapCore 將日誌交給目前的 coreGeneration，讓既有 logger 與其 With 衍生的 logger
+// 在替換後立即使用新的輸出。
+type swapCore struct {
+	current *atomic.Pointer[coreGeneration]
+	fields  []zapcore.Field
+	derived atomic.Pointer[derivedCore]
+}
+
+// derivedCore 快取 generation 套用 fields 後的 core，替換 generation 後重新建立。
+type derivedCore struct {
+	generation *coreGeneration
+	core       zapcore.Core
+}
+
+func newSwapCore(generation *coreGeneration) *swapCore {
+	core := &swapCore{current: new(atomic.Pointer[coreGeneration])}
+	core.current.Store(generation)
+	return core
+}
+
+// swap 改用 generation 並回傳先前的 generation。
+func (c *swapCore) swap(generation *coreGeneration) *coreGeneration {
+	return c.current.Swap(generation)
+}
+
+func (c *swapCore) load() (*coreGeneration, zapcore.Core) {
+	generation := c.current.Load()
+	if len(c.fields) == 0 {
+		return generation, generation.core
+	}
+	if derived := c.derived.Load(); derived != nil && derived.generation == generation {
+		return generation, derived.core
+	}
+	derived := &derivedCore{generation: generation, core: generation.core.With(c.fields)}
+	c.derived.Store(derived)
+	return generation, derived.core
+}
+
+func (c *swapCore) Enabled(level zapcore.Level) bool {
+	_, core := c.load()
+	return core.Enabled(level)
+}
+
+// Level 回傳目前 core 的最低啟用級別，供 zapcore.LevelOf 使用。
+func (c *swapCore) Level() zapcore.Level {
+	_, core := c.load()
+	return zapcore.LevelOf(core)
+}
+
+func (c *swapCore) With(fields []zapcore.Field) zapcore.Core {
+	return &swapCore{current: c.current, fields: slices.Concat(c.fields, fields)}
+}
+
+func (c *swapCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	for {
+		generation, core := c.load()
+		generation.inflight.Add(1)
+		if !generation.retired.Load() {
+			result := core.Check(entry, checked)
+			if result == nil {
+				generation.leave()
+				return nil
+			}
+			return result.AddCore(entry, generation.release)
+		}
+		generation.leave()
+		if c.current.Load() == generation {
+			// Instance 已關閉：維持關閉後寫入回報錯誤的既有行為。
+			return core.Check(entry, checked)
+		}
+	}
+}
+
+func (c *swapCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	_, core := c.load()
+	return core.Write(entry, fields)
+}
+
+func (c *swapCore) Sync() error {
+	_, core := c.load()
+	return core.Sync()
+}
@@ -11,6 +11,7 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -103,8 +104,10 @@ func resetGlobalState(t testing.TB) {
 
 	configureMu.Lock()
 	globalLogger = nil
+	globalInstance = nil
 	globalConfig = nil
 	zapGlobalLevel = zap.NewAtomicLevel()
+	globalLevels = nil
 	configured = false
 	globalCleanup = nil
 	configureMu.Unlock()
@@ -118,7 +121,7 @@ func registerGlobalCleanup(t *testing.T) {
 	})
 }
 
-func registerFileCleanup(t *testing.T, file *os.File) {
+func registerFileCleanup(t *testing.T, file io.Closer) {
 	t.Helper()
 	if file == nil {
 		t.Fatal("測試日誌檔案不可為 nil")
@@ -836,6 +839,130 @@ func TestNewCleanupIsConcurrentAndIdempotent(t *testing.T) {
 	}
 }
 
+func TestNewWithSplitOutput(t *testing.T) {
+	base := t.TempDir()
+	instance, err := New(&Config{
+		Level:        "info",
+		Format:       "console",
+		Outputs:      []string{"split"},
+		LogPath:      base,
+		SplitPrefix:  "svc",
+		SplitFormat:  "json",
+		ColorEnabled: true,
+	})
+	if err != nil {
+		t.Fatalf("建立 split Instance 失敗：%v", err)
+	}
+	instance.Logger().Debug("不應寫入")
+	instance.Logger().Warn("警告")
+	instance.Logger().Error("錯誤")
+	if err := instance.Close(); err != nil {
+		t.Fatalf("關閉 Instance 失敗：%v", err)
+	}
+
+	splitOut, ok := instance.closers[0].(*SplitOutput)
+	if !ok {
+		t.Fatalf("Instance 應擁有 SplitOutput，實際為 %T", instance.closers[0])
+	}
+	if _, err := splitOut.Write(zapcore.ErrorLevel, []byte("closed\n")); !errors.Is(err, os.ErrClosed) {
+		t.Fatalf("Instance.Close 後寫入錯誤 = %v，預期包裝 os.ErrClosed", err)
+	}
+
+	date := time.Now().Format(dailyRotationLayout)
+	info, err := os.ReadFile(filepath.Join(base, "svc-info-"+date+".log"))
+	if err != nil {
+		t.Fatalf("讀取 info 檔失敗：%v", err)
+	}
+	if !strings.Contains(string(info), "logger initialized") || strings.Contains(string(info), "不應寫入") {
+		t.Fatalf("info 檔內容不符合 level 設定：%s", info)
+	}
+	warn, err := os.ReadFile(filepath.Join(base, "svc-warn-"+date+".log"))
+	if err != nil {
+		t.Fatalf("讀取 warn 檔失敗：%v", err)
+	}
+	if !strings.Contains(string(warn), `"msg":"警告"`) || strings.Contains(string(warn), "\x1b[") {
+		t.Fatalf("warn 檔應為不含 ANSI 的 JSON：%s", warn)
+	}
+	errorLog, err := os.ReadFile(filepath.Join(base, "svc-error-"+date+".log"))
+	if err != nil {
+		t.Fatalf("讀取 error 檔失敗：%v", err)
+	}
+	if !strings.Contains(string(errorLog), `"msg":"錯誤"`) {
+		t.Fatalf("error 檔缺少錯誤訊息：%s", errorLog)
+	}
+}
+
+func TestRotatingFileCoreSwitchesDate(t *testing.T) {
+	base := t.TempDir()
+	now := time.Date(2026, time.July, 29, 23, 0, 0, 0, time.Local)
+	clock := newManualRotationClock(now)
+	settings, err := resolveFileOutputOptions()
+	if err != nil {
+		t.Fatalf("解析 options 失敗：%v", err)
+	}
+	cfg := fileOutputTestConfig(base, "")
+	core, fileOut, err := newRotatingFileCore(
+		cfg,
+		buildEncoderConfig(cfg),
+		zap.NewAtomicLevelAt(zapcore.InfoLevel),
+		settings,
+		clock,
+	)
+	if err != nil {
+		t.Fatalf("建立換檔 file core 失敗：%v", err)
+	}
+	t.Cleanup(func() { _ = fileOut.Close() })
+	logger := zap.New(core)
+
+	logger.Debug("不應寫入")
+	logger.Info("第一天")
+	timer := clock.nextTimer(t)
+	nextDay := time.Date(2026, time.July, 30, 0, 0, 0, 0, time.Local)
+	clock.setNow(nextDay)
+	timer.fire(nextDay)
+	clock.nextTimer(t)
+	logger.Error("第二天")
+	if err := fileOut.Close(); err != nil {
+		t.Fatalf("關閉 file output 失敗：%v", err)
+	}
+
+	assertRetainedLogFiles(t, base, "2026-07-29.log", "2026-07-30.log")
+	for leaf, want := range map[string]string{"2026-07-29.log": "第一天", "2026-07-30.log": "第二天"} {
+		//nolint:gosec // 測試只讀取 t.TempDir 內的預期檔案。
+		content, err := os.ReadFile(filepath.Join(base, leaf))
+		if err != nil {
+			t.Fatalf("讀取 %s 失敗：%v", leaf, err)
+		}
+		if !strings.Contains(string(content), want) || strings.Contains(string(content), "不應寫入") {
+			t.Fatalf("%s 內容 = %s，預期只包含 %q", leaf, content, want)
+		}
+	}
+}
+
+func TestNewFileOutputWithoutFileNameIsOwnedByInstance(t *testing.T) {
+	base := t.TempDir()
+	instance, err := New(fileOutputTestConfig(base, ""))
+	if err != nil {
+		t.Fatalf("建立 Instance 失敗：%v", err)
+	}
+	if err := instance.Close(); err != nil {
+		t.Fatalf("關閉 Instance 失敗：%v", err)
+	}
+
+	fileOut, ok := instance.closers[0].(*SplitOutput)
+	if !ok {
+		t.Fatalf("未指定 FileName 時應由 Instance 擁有換檔輸出，實際為 %T", instance.closers[0])
+	}
+	select {
+	case <-fileOut.done:
+	default:
+		t.Fatal("Instance.Close 後換檔 worker 應已停止")
+	}
+	if _, err := os.Stat(filepath.Join(base, time.Now().Format(dailyRotationLayout)+".log")); err != nil {
+		t.Fatalf("預期建立日期檔：%v", err)
+	}
+}
+
 func TestConfigureCanRetryAfterFailure(t *testing.T) {
 	resetGlobalState(t)
 	t.Cleanup(func() { resetGlobalState(t) })
@@ -0,0 +1,25 @@
+//go:build !windows
+
+package zlogger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// syncRootedDir 同步 root 目錄本身，確保先前的改名在斷電後仍然存在。
+func syncRootedDir(root *os.Root, baseDir string) error {
+	directory, err := root.Open(".")
+	if err != nil {
+		return fmt.Errorf("開啟日誌 root %q 目錄: %w", baseDir, err)
+	}
+	err = directory.Sync()
+	if closeErr := directory.Close(); closeErr != nil {
+		err = errors.Join(err, fmt.Errorf("關閉日誌 root %q 目錄: %w", baseDir, closeErr))
+	}
+	if err != nil {
+		return fmt.Errorf("同步日誌 root %q 目錄: %w", baseDir, err)
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+//go:build windows
+
+package zlogger
+
+import "os"
+
+// syncRootedDir 在 Windows 不執行任何動作：目錄 handle 無法 FlushFileBuffers，
+// NTFS 以 metadata journal 保存改名。
+func syncRootedDir(*os.Root, string) error {
+	return nil
+}
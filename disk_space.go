@@ -0,0 +1,137 @@
+package zlogger
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// ErrLowDiskSpace 表示日誌目錄所在磁碟的可用空間低於 WithDiskSpaceGuard 的門檻。
+var ErrLowDiskSpace = errors.New("日誌磁碟可用空間不足")
+
+// diskSpaceCheckInterval 是寫入路徑重新查詢可用空間的最短間隔。
+const diskSpaceCheckInterval = time.Second
+
+// diskSpaceProbe 回傳 directory 所在檔案系統可供非特權使用者使用的位元組數。
+type diskSpaceProbe func(directory string) (uint64, error)
+
+// WithDiskSpaceGuard 在日誌目錄可用空間低於 minFreeBytes 時，只寫入 minLevel 以上的日誌。
+//
+// 可用空間最多每秒查詢一次。進入低空間狀態時以 InternalEventDiskSpace 與包裝
+// ErrLowDiskSpace 的錯誤回報一次，空間恢復後自動回到正常寫入。無法查詢可用空間的
+// 平台或查詢失敗時維持正常寫入，並回報一次查詢錯誤。
+func WithDiskSpaceGuard(minFreeBytes uint64, minLevel zapcore.Level) FileOutputOption {
+	return fileOutputOptionFunc(func(settings *fileOutputSettings) error {
+		if minFreeBytes == 0 {
+			return fmt.Errorf("%w: 磁碟空間門檻必須大於 0", ErrInvalidConfig)
+		}
+		if minLevel < zapcore.DebugLevel || minLevel > zapcore.FatalLevel {
+			return fmt.Errorf("%w: 磁碟空間不足時保留的級別 %s 無效", ErrInvalidConfig, minLevel)
+		}
+		settings.minFreeBytes = minFreeBytes
+		settings.lowDiskLevel = minLevel
+		return nil
+	})
+}
+
+// diskSpaceGuard 依快取的可用空間決定是否丟棄低級別日誌。
+type diskSpaceGuard struct {
+	directory string
+	settings  fileOutputSettings
+	probe     diskSpaceProbe
+	now       func() time.Time
+
+	mu          sync.Mutex
+	checkedAt   time.Time
+	checked     bool
+	low         bool
+	probeFailed bool
+}
+
+// newDiskSpaceGuard 依設定建立 guard；未啟用時回傳 nil。
+func newDiskSpaceGuard(directory string, settings fileOutputSettings, now func() time.Time) *diskSpaceGuard {
+	if settings.minFreeBytes == 0 {
+		return nil
+	}
+	probe := settings.diskSpaceProbe
+	if probe == nil {
+		probe = availableDiskSpace
+	}
+	return &diskSpaceGuard{directory: directory, settings: settings, probe: probe, now: now}
+}
+
+// allow 回傳 level 是否可寫入；nil guard 一律允許。狀態切換的事件於釋放 mutex 後回報。
+func (g *diskSpaceGuard) allow(level zapcore.Level) bool {
+	if g == nil {
+		return true
+	}
+
+	g.mu.Lock()
+	var event error
+	now := g.now()
+	if !g.checked || now.Sub(g.checkedAt) >= diskSpaceCheckInterval {
+		g.checked = true
+		g.checkedAt = now
+		event = g.refresh()
+	}
+	low := g.low
+	g.mu.Unlock()
+
+	g.settings.reportInternalEvent(InternalEventDiskSpace, g.directory, event)
+	return !low || level >= g.settings.lowDiskLevel
+}
+
+// refresh 重新查詢可用空間，只在進入低空間或首次查詢失敗時回傳事件；呼叫端必須持有 mutex。
+func (g *diskSpaceGuard) refresh() error {
+	free, err := g.probe(g.directory)
+	if err != nil {
+		g.low = false
+		if g.probeFailed {
+			return nil
+		}
+		g.probeFailed = true
+		return fmt.Errorf("查詢日誌目錄 %q 可用空間: %w", g.directory, err)
+	}
+	g.probeFailed = false
+
+	wasLow := g.low
+	g.low = free < g.settings.minFreeBytes
+	if g.low && !wasLow {
+		return fmt.Errorf(
+			"%w: 可用 %d bytes，低於 %d bytes，只寫入 %s 以上級別",
+			ErrLowDiskSpace,
+			free,
+			g.settings.minFreeBytes,
+			g.settings.lowDiskLevel,
+		)
+	}
+	return nil
+}
+
+// diskGuardCore 在低空間時於 Check 階段丟棄低級別日誌，避免編碼與寫入。
+type diskGuardCore struct {
+	zapcore.Core
+	guard *diskSpaceGuard
+}
+
+// withDiskSpaceGuard 以 guard 包裝 core；guard 為 nil 時原樣回傳。
+func withDiskSpaceGuard(core zapcore.Core, guard *diskSpaceGuard) zapcore.Core {
+	if guard == nil {
+		return core
+	}
+	return &diskGuardCore{Core: core, guard: guard}
+}
+
+func (c *diskGuardCore) With(fields []zapcore.Field) zapcore.Core {
+	return &diskGuardCore{Core: c.Core.With(fields), guard: c.guard}
+}
+
+func (c *diskGuardCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Core.Enabled(entry.Level) || !c.guard.allow(entry.Level) {
+		return checked
+	}
+	return c.Core.Check(entry, checked)
+}
@@ -0,0 +1,12 @@
+//go:build !(linux || darwin || freebsd || dragonfly || windows)
+
+package zlogger
+
+import (
+	"errors"
+	"fmt"
+)
+
+func availableDiskSpace(directory string) (uint64, error) {
+	return 0, fmt.Errorf("查詢 %q 可用空間: %w", directory, errors.ErrUnsupported)
+}
@@ -0,0 +1,17 @@
+//go:build linux || darwin || freebsd || dragonfly
+
+package zlogger
+
+import (
+	"fmt"
+	"syscall"
+)
+
+func availableDiskSpace(directory string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(directory, &stat); err != nil {
+		return 0, fmt.Errorf("statfs %q: %w", directory, err)
+	}
+	//nolint:gosec,unconvert // 各平台欄位型別不同，可用區塊數與區塊大小皆不為負。
+	return uint64(stat.Bavail) * uint64(stat.Bsize), nil
+}
@@ -0,0 +1,165 @@
+package zlogger
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestWithDiskSpaceGuardRejectsInvalidSettings(t *testing.T) {
+	tests := []struct {
+		name    string
+		minFree uint64
+		level   zapcore.Level
+	}{
+		{name: "門檻為 0", minFree: 0, level: zapcore.ErrorLevel},
+		{name: "級別過低", minFree: 1, level: zapcore.DebugLevel - 1},
+		{name: "級別過高", minFree: 1, level: zapcore.FatalLevel + 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := filepath.Join(t.TempDir(), "不應建立")
+			output, err := NewSplitOutputWithOptions(base, "app", WithDiskSpaceGuard(tt.minFree, tt.level))
+			if output != nil {
+				_ = output.Close()
+				t.Fatal("無效設定不應回傳 SplitOutput")
+			}
+			if !errors.Is(err, ErrInvalidConfig) {
+				t.Fatalf("錯誤 = %v，預期 ErrInvalidConfig", err)
+			}
+			assertPathDoesNotExist(t, base)
+		})
+	}
+}
+
+func TestSplitOutputDiskSpaceGuard(t *testing.T) {
+	base := t.TempDir()
+	now := time.Date(2026, time.July, 29, 10, 0, 0, 0, time.Local)
+	clock := newManualRotationClock(now)
+	var free atomic.Uint64
+	free.Store(100)
+	handler := &recordingErrorHandler{}
+	settings, err := resolveFileOutputOptions(
+		WithDiskSpaceGuard(1000, zapcore.ErrorLevel),
+		WithErrorHandler(handler.handle),
+	)
+	if err != nil {
+		t.Fatalf("解析 options 失敗：%v", err)
+	}
+	settings.diskSpaceProbe = func(directory string) (uint64, error) {
+		if directory != base {
+			t.Errorf("查詢目錄 = %q，預期 %q", directory, base)
+		}
+		return free.Load(), nil
+	}
+	output, err := newSplitOutputWithSettings(base, "app", clock, openSplitFilesWithPermissions, settings)
+	if err != nil {
+		t.Fatalf("建立 SplitOutput 失敗：%v", err)
+	}
+	t.Cleanup(func() { _ = output.Close() })
+	logger := zap.New(output.core(zapcore.NewConsoleEncoder(zapcore.EncoderConfig{MessageKey: "msg"}), nil))
+
+	logger.Info("空間不足時丟棄")
+	logger.Error("空間不足仍保留")
+	clock.setNow(now.Add(diskSpaceCheckInterval))
+	logger.Warn("仍在低空間")
+	if written, err := output.Write(zapcore.InfoLevel, []byte("直接寫入同樣丟棄\n")); err != nil || written == 0 {
+		t.Fatalf("直接寫入 = %d, %v，預期視為成功", written, err)
+	}
+
+	free.Store(5000)
+	clock.setNow(now.Add(2 * diskSpaceCheckInterval))
+	logger.Info("空間恢復")
+	if err := output.Sync(); err != nil {
+		t.Fatalf("同步失敗：%v", err)
+	}
+
+	assertFileContent(t, filepath.Join(base, "app-info-2026-07-29.log"), "空間恢復\n")
+	assertFileContent(t, filepath.Join(base, "app-warn-2026-07-29.log"), "")
+	assertFileContent(t, filepath.Join(base, "app-error-2026-07-29.log"), "空間不足仍保留\n")
+	events := handler.snapshot()
+	if len(events) != 1 || events[0].Kind != InternalEventDiskSpace || events[0].Path != base ||
+		!errors.Is(events[0].Err, ErrLowDiskSpace) {
+		t.Fatalf("事件 = %+v，預期一次低空間事件", events)
+	}
+}
+
+func TestDiskSpaceGuardKeepsWritingWhenProbeFails(t *testing.T) {
+	probeErr := errors.New("無法查詢")
+	handler := &recordingErrorHandler{}
+	now := time.Date(2026, time.July, 29, 10, 0, 0, 0, time.UTC)
+	settings := fileOutputSettings{
+		minFreeBytes:   1000,
+		lowDiskLevel:   zapcore.ErrorLevel,
+		errorHandler:   handler.handle,
+		diskSpaceProbe: func(string) (uint64, error) { return 0, probeErr },
+	}
+	guard := newDiskSpaceGuard("logs", settings, func() time.Time { return now })
+
+	for range 3 {
+		if !guard.allow(zapcore.InfoLevel) {
+			t.Fatal("查詢失敗時應維持正常寫入")
+		}
+		now = now.Add(diskSpaceCheckInterval)
+	}
+	events := handler.snapshot()
+	if len(events) != 1 || !errors.Is(events[0].Err, probeErr) {
+		t.Fatalf("事件 = %+v，預期只回報一次查詢錯誤", events)
+	}
+}
+
+func TestNewFileOutputDiskSpaceGuard(t *testing.T) {
+	base := t.TempDir()
+	settings, err := resolveFileOutputOptions(WithDiskSpaceGuard(1000, zapcore.WarnLevel))
+	if err != nil {
+		t.Fatalf("解析 options 失敗：%v", err)
+	}
+	settings.diskSpaceProbe = func(string) (uint64, error) { return 1, nil }
+	settings.errorHandler = func(InternalEvent) {}
+	cfg := fileOutputTestConfig(base, "app.log")
+	core, file, err := newFileCoreWithSettings(
+		cfg,
+		buildEncoderConfig(cfg),
+		zap.NewAtomicLevelAt(zapcore.InfoLevel),
+		settings,
+	)
+	if err != nil {
+		t.Fatalf("建立 file core 失敗：%v", err)
+	}
+	logger := zap.New(core).With(zap.String("component", "guard"))
+	logger.Info("丟棄")
+	logger.Warn("保留")
+	if err := file.Close(); err != nil {
+		t.Fatalf("關閉 file output 失敗：%v", err)
+	}
+
+	//nolint:gosec // 測試只讀取 t.TempDir 內的預期檔案。
+	content, err := os.ReadFile(filepath.Join(base, "app.log"))
+	if err != nil {
+		t.Fatalf("讀取日誌失敗：%v", err)
+	}
+	if strings.Contains(string(content), "丟棄") || !strings.Contains(string(content), "保留") {
+		t.Fatalf("日誌內容 = %s，預期只包含 WARN", content)
+	}
+}
+
+func TestAvailableDiskSpace(t *testing.T) {
+	free, err := availableDiskSpace(t.TempDir())
+	if errors.Is(err, errors.ErrUnsupported) {
+		t.Skipf("平台不支援查詢可用空間：%v", err)
+	}
+	if err != nil {
+		t.Fatalf("查詢可用空間失敗：%v", err)
+	}
+	if free == 0 {
+		t.Fatal("暫存目錄可用空間不應為 0")
+	}
+}
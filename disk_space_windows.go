@@ -0,0 +1,29 @@
+//go:build windows
+
+package zlogger
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+var procGetDiskFreeSpaceExW = syscall.NewLazyDLL("kernel32.dll").NewProc("GetDiskFreeSpaceExW")
+
+func availableDiskSpace(directory string) (uint64, error) {
+	path, err := syscall.UTF16PtrFromString(directory)
+	if err != nil {
+		return 0, fmt.Errorf("GetDiskFreeSpaceExW %q: %w", directory, err)
+	}
+	var available uint64
+	result, _, callErr := procGetDiskFreeSpaceExW.Call(
+		uintptr(unsafe.Pointer(path)),
+		uintptr(unsafe.Pointer(&available)),
+		0,
+		0,
+	)
+	if result == 0 {
+		return 0, fmt.Errorf("GetDiskFreeSpaceExW %q: %w", directory, callErr)
+	}
+	return available, nil
+}
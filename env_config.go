@@ -0,0 +1,243 @@
+package zlogger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ConfigPatchFromEnv 以環境變數建立 ConfigPatch，只填入有設定的變數。
+//
+// 變數名稱為 prefix 加上大寫的 mapstructure key，例如 prefix 為 "LOG" 時讀取
+// LOG_LEVEL、LOG_OUTPUTS、LOG_ADD_CALLER；prefix 未以 "_" 結尾時自動補上，空字串表示
+// 不加前綴。取樣設定使用 SAMPLING_INITIAL、SAMPLING_THEREAFTER、SAMPLING_TICK 與
+// SAMPLING_LEVELS（格式為 "error=1000:10,warn=100:0"），任一存在時必須提供
+// SAMPLING_INITIAL。
+//
+// 字串變數設為空字串表示明確的空值。bool 只接受 true、false、1、0；list 以逗號分隔，
+// 不接受空白元素。解析失敗時回傳包裝 ErrInvalidConfig 且指出變數名稱的錯誤；
+// 欄位值的其餘檢查由 Resolve 執行。
+func ConfigPatchFromEnv(prefix string) (*ConfigPatch, error) {
+	return configPatchFromLookup(prefix, os.LookupEnv)
+}
+
+// envReader 依 prefix 讀取變數並保留第一個解析錯誤。
+type envReader struct {
+	prefix string
+	lookup func(string) (string, bool)
+	err    error
+}
+
+func configPatchFromLookup(prefix string, lookup func(string) (string, bool)) (*ConfigPatch, error) {
+	if prefix != "" && !strings.HasSuffix(prefix, "_") {
+		prefix += "_"
+	}
+	env := &envReader{prefix: prefix, lookup: lookup}
+
+	patch := &ConfigPatch{
+		Level:         env.levelSpec("LEVEL"),
+		Format:        env.string("FORMAT"),
+		Outputs:       env.list("OUTPUTS"),
+		LogPath:       env.string("LOG_PATH"),
+		FileName:      env.string("FILE_NAME"),
+		SplitPrefix:   env.string("SPLIT_PREFIX"),
+		SplitFormat:   env.string("SPLIT_FORMAT"),
+		AddCaller:     env.bool("ADD_CALLER"),
+		AddStacktrace: env.bool("ADD_STACKTRACE"),
+		Development:   env.bool("DEVELOPMENT"),
+		ColorEnabled:  env.bool("COLOR_ENABLED"),
+		Sampling:      env.sampling(),
+	}
+	if env.err != nil {
+		return nil, env.err
+	}
+	return patch, nil
+}
+
+func (e *envReader) get(key string) (string, string, bool) {
+	name := e.prefix + key
+	if e.err != nil {
+		return name, "", false
+	}
+	value, ok := e.lookup(name)
+	return name, value, ok
+}
+
+func (e *envReader) fail(name string, err error) {
+	e.err = fmt.Errorf("%w: 環境變數 %s: %w", ErrInvalidConfig, name, err)
+}
+
+func (e *envReader) string(key string) *string {
+	_, value, ok := e.get(key)
+	if !ok {
+		return nil
+	}
+	return &value
+}
+
+func (e *envReader) levelSpec(key string) *string {
+	name, value, ok := e.get(key)
+	if !ok {
+		return nil
+	}
+	if _, err := parseLevelSpec(value); err != nil {
+		e.err = fmt.Errorf("環境變數 %s: %w", name, err)
+		return nil
+	}
+	return &value
+}
+
+func (e *envReader) bool(key string) *bool {
+	name, value, ok := e.get(key)
+	if !ok {
+		return nil
+	}
+	parsed, err := parseStrictBool(value)
+	if err != nil {
+		e.fail(name, err)
+		return nil
+	}
+	return &parsed
+}
+
+func (e *envReader) list(key string) *[]string {
+	name, value, ok := e.get(key)
+	if !ok {
+		return nil
+	}
+	items, err := parseConfigList(value)
+	if err != nil {
+		e.fail(name, err)
+		return nil
+	}
+	return &items
+}
+
+func (e *envReader) int(key string) (int, bool) {
+	name, value, ok := e.get(key)
+	if !ok {
+		return 0, false
+	}
+	parsed, err := parseConfigInt(value)
+	if err != nil {
+		e.fail(name, err)
+		return 0, false
+	}
+	return parsed, true
+}
+
+// sampling 讀取 SAMPLING_* 變數；全部未設定時回傳 nil。
+func (e *envReader) sampling() *SamplingConfig {
+	initial, hasInitial := e.int("SAMPLING_INITIAL")
+	thereafter, hasThereafter := e.int("SAMPLING_THEREAFTER")
+	tick := e.string("SAMPLING_TICK")
+	levels := e.samplingLevels("SAMPLING_LEVELS")
+	if e.err != nil || (!hasInitial && !hasThereafter && tick == nil && levels == nil) {
+		return nil
+	}
+	if !hasInitial {
+		e.fail(e.prefix+"SAMPLING_INITIAL", errSamplingInitialRequired)
+		return nil
+	}
+
+	sampling := &SamplingConfig{Initial: initial, Thereafter: thereafter, Levels: levels}
+	if tick != nil {
+		if err := checkSamplingTick(*tick); err != nil {
+			e.fail(e.prefix+"SAMPLING_TICK", err)
+			return nil
+		}
+		sampling.Tick = *tick
+	}
+	return sampling
+}
+
+func (e *envReader) samplingLevels(key string) map[string]SamplingLevel {
+	name, value, ok := e.get(key)
+	if !ok {
+		return nil
+	}
+	levels, err := parseSamplingLevels(value)
+	if err != nil {
+		e.fail(name, err)
+		return nil
+	}
+	return levels
+}
+
+// errSamplingInitialRequired 表示設定了其他取樣欄位卻未提供 initial。
+var errSamplingInitialRequired = errors.New("未設定，啟用取樣時必須提供")
+
+// parseStrictBool 只接受 true、false、1、0，不分大小寫。
+func parseStrictBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "true", "1":
+		return true, nil
+	case "false", "0":
+		return false, nil
+	default:
+		return false, fmt.Errorf("%q 不是 true、false、1 或 0", value)
+	}
+}
+
+// parseConfigList 解析逗號分隔的清單，不接受空字串或空白元素。
+func parseConfigList(value string) ([]string, error) {
+	if value == "" {
+		return nil, errors.New("不可為空")
+	}
+	items := strings.Split(value, ",")
+	for index, item := range items {
+		items[index] = strings.TrimSpace(item)
+		if items[index] == "" {
+			return nil, fmt.Errorf("%q 含有空白元素", value)
+		}
+	}
+	return items, nil
+}
+
+func parseConfigInt(value string) (int, error) {
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%q 不是整數", value)
+	}
+	return parsed, nil
+}
+
+func checkSamplingTick(value string) error {
+	if _, err := time.ParseDuration(value); err != nil {
+		return fmt.Errorf("%q 無法解析為 duration", value)
+	}
+	return nil
+}
+
+// parseSamplingLevels 解析 "error=1000:10,warn=100:0" 格式的級別覆寫。
+func parseSamplingLevels(value string) (map[string]SamplingLevel, error) {
+	items, err := parseConfigList(value)
+	if err != nil {
+		return nil, err
+	}
+	levels := make(map[string]SamplingLevel, len(items))
+	for _, item := range items {
+		level, counts, hasLevel := strings.Cut(item, "=")
+		initialText, thereafterText, hasCounts := strings.Cut(counts, ":")
+		if !hasLevel || !hasCounts {
+			return nil, fmt.Errorf("項目 %q 必須是 level=initial:thereafter", item)
+		}
+		level = strings.ToLower(strings.TrimSpace(level))
+		if _, err := parseStrictLevel(level); err != nil {
+			return nil, fmt.Errorf("項目 %q: %w", item, err)
+		}
+		if _, exists := levels[level]; exists {
+			return nil, fmt.Errorf("級別 %q 重複", level)
+		}
+		initial, initialErr := strconv.Atoi(strings.TrimSpace(initialText))
+		thereafter, thereafterErr := strconv.Atoi(strings.TrimSpace(thereafterText))
+		if initialErr != nil || thereafterErr != nil {
+			return nil, fmt.Errorf("項目 %q 的數量不是整數", item)
+		}
+		levels[level] = SamplingLevel{Initial: initial, Thereafter: thereafter}
+	}
+	return levels, nil
+}
@@ -0,0 +1,121 @@
+package zlogger
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestConfigPatchFromEnvFillsEveryField(t *testing.T) {
+	env := map[string]string{
+		"APP_LOG_LEVEL":               "debug,db=warn",
+		"APP_LOG_FORMAT":              "json",
+		"APP_LOG_OUTPUTS":             "console, split",
+		"APP_LOG_LOG_PATH":            "/var/log/app",
+		"APP_LOG_FILE_NAME":           "",
+		"APP_LOG_SPLIT_PREFIX":        "svc",
+		"APP_LOG_SPLIT_FORMAT":        "console",
+		"APP_LOG_ADD_CALLER":          "false",
+		"APP_LOG_ADD_STACKTRACE":      "1",
+		"APP_LOG_DEVELOPMENT":         "TRUE",
+		"APP_LOG_COLOR_ENABLED":       "0",
+		"APP_LOG_SAMPLING_INITIAL":    "100",
+		"APP_LOG_SAMPLING_THEREAFTER": "10",
+		"APP_LOG_SAMPLING_TICK":       "500ms",
+		"APP_LOG_SAMPLING_LEVELS":     "error=1000:0, WARN=200:20",
+	}
+	for name, value := range env {
+		t.Setenv(name, value)
+	}
+
+	patch, err := ConfigPatchFromEnv("APP_LOG")
+	if err != nil {
+		t.Fatalf("ConfigPatchFromEnv 失敗：%v", err)
+	}
+	cfg, err := patch.Resolve()
+	if err != nil {
+		t.Fatalf("Resolve 失敗：%v", err)
+	}
+
+	if cfg.Level != "debug,db=warn" || cfg.Format != "json" || cfg.LogPath != "/var/log/app" ||
+		cfg.SplitPrefix != "svc" || cfg.SplitFormat != "console" {
+		t.Fatalf("字串欄位 = %+v", cfg)
+	}
+	if !slices.Equal(cfg.Outputs, []string{"console", "split"}) {
+		t.Fatalf("Outputs = %v", cfg.Outputs)
+	}
+	if patch.FileName == nil || *patch.FileName != "" {
+		t.Fatalf("FileName = %v，預期明確空字串", patch.FileName)
+	}
+	if cfg.AddCaller || !cfg.AddStacktrace || !cfg.Development || cfg.ColorEnabled {
+		t.Fatalf("bool 欄位 = %+v", cfg)
+	}
+	sampling := cfg.Sampling
+	if sampling == nil || sampling.Initial != 100 || sampling.Thereafter != 10 || sampling.Tick != "500ms" {
+		t.Fatalf("Sampling = %+v", sampling)
+	}
+	if sampling.Levels["error"] != (SamplingLevel{Initial: 1000}) ||
+		sampling.Levels["warn"] != (SamplingLevel{Initial: 200, Thereafter: 20}) {
+		t.Fatalf("Sampling.Levels = %+v", sampling.Levels)
+	}
+}
+
+func TestConfigPatchFromEnvLeavesUnsetFieldsNil(t *testing.T) {
+	t.Setenv("SVC_LEVEL", "warn")
+	t.Setenv("SVC_FORMATX", "json")
+
+	patch, err := ConfigPatchFromEnv("SVC_")
+	if err != nil {
+		t.Fatalf("ConfigPatchFromEnv 失敗：%v", err)
+	}
+	if patch.Level == nil || *patch.Level != "warn" {
+		t.Fatalf("Level = %v", patch.Level)
+	}
+	if *patch != (ConfigPatch{Level: patch.Level}) {
+		t.Fatalf("patch = %+v，預期只設定 Level", patch)
+	}
+}
+
+func TestConfigPatchFromEnvRejectsInvalidValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		variable string
+		value    string
+	}{
+		{name: "bool 使用 yes", variable: "LOG_ADD_CALLER", value: "yes"},
+		{name: "bool 為空字串", variable: "LOG_DEVELOPMENT", value: ""},
+		{name: "list 為空字串", variable: "LOG_OUTPUTS", value: ""},
+		{name: "list 含空白元素", variable: "LOG_OUTPUTS", value: "console,,file"},
+		{name: "list 結尾逗號", variable: "LOG_OUTPUTS", value: "console,"},
+		{name: "未知級別", variable: "LOG_LEVEL", value: "verbose"},
+		{name: "整數無法解析", variable: "LOG_SAMPLING_INITIAL", value: "ten"},
+		{name: "缺少 SAMPLING_INITIAL", variable: "LOG_SAMPLING_THEREAFTER", value: "10"},
+		{name: "tick 無法解析", variable: "LOG_SAMPLING_TICK", value: "每秒"},
+		{name: "級別覆寫格式錯誤", variable: "LOG_SAMPLING_LEVELS", value: "error=10"},
+		{name: "級別覆寫未知級別", variable: "LOG_SAMPLING_LEVELS", value: "trace=1:0"},
+		{name: "級別覆寫重複", variable: "LOG_SAMPLING_LEVELS", value: "error=1:0,ERROR=2:0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(tt.variable, tt.value)
+			if strings.HasPrefix(tt.variable, "LOG_SAMPLING_") && tt.variable != "LOG_SAMPLING_INITIAL" &&
+				tt.variable != "LOG_SAMPLING_THEREAFTER" {
+				t.Setenv("LOG_SAMPLING_INITIAL", "10")
+			}
+
+			patch, err := ConfigPatchFromEnv("LOG")
+			if patch != nil || !errors.Is(err, ErrInvalidConfig) {
+				t.Fatalf("patch = %+v, 錯誤 = %v，預期 ErrInvalidConfig", patch, err)
+			}
+			wantName := tt.variable
+			if tt.variable == "LOG_SAMPLING_THEREAFTER" {
+				wantName = "LOG_SAMPLING_INITIAL"
+			}
+			if !strings.Contains(err.Error(), wantName) {
+				t.Fatalf("錯誤 %q 未指出變數 %s", err, wantName)
+			}
+		})
+	}
+}
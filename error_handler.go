@@ -0,0 +1,153 @@
+package zlogger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// InternalEventKind 分類檔案輸出與設定監看在背景發生的錯誤。
+type InternalEventKind uint8
+
+const (
+	// InternalEventRotation 表示依週期或大小換檔、更新 current symlink 失敗。
+	InternalEventRotation InternalEventKind = iota + 1
+	// InternalEventWrite 表示寫入日誌檔失敗。
+	InternalEventWrite
+	// InternalEventSync 表示同步日誌檔失敗。
+	InternalEventSync
+	// InternalEventClose 表示關閉日誌檔失敗。
+	InternalEventClose
+	// InternalEventRetention 表示清理過期日誌失敗。
+	InternalEventRetention
+	// InternalEventCompression 表示背景壓縮失敗。
+	InternalEventCompression
+	// InternalEventHook 表示換檔 hook 發生 panic。
+	InternalEventHook
+	// InternalEventReopen 表示定期檢查發現檔案被刪除或移動，或檢查本身失敗。
+	InternalEventReopen
+	// InternalEventDiskSpace 表示可用空間低於門檻而開始丟棄低級別日誌，或無法查詢可用空間。
+	InternalEventDiskSpace
+	// InternalEventConfigReload 表示監看的設定檔無法讀取、解析或變更被拒絕，或 Reload
+	// 無法關閉舊輸出。
+	InternalEventConfigReload
+)
+
+// String 回傳事件種類的固定識別字串。
+func (k InternalEventKind) String() string {
+	switch k {
+	case InternalEventRotation:
+		return "rotation"
+	case InternalEventWrite:
+		return "write"
+	case InternalEventSync:
+		return "sync"
+	case InternalEventClose:
+		return "close"
+	case InternalEventRetention:
+		return "retention"
+	case InternalEventCompression:
+		return "compression"
+	case InternalEventHook:
+		return "hook"
+	case InternalEventReopen:
+		return "reopen"
+	case InternalEventDiskSpace:
+		return "disk space"
+	case InternalEventConfigReload:
+		return "config reload"
+	default:
+		return fmt.Sprintf("InternalEventKind(%d)", uint8(k))
+	}
+}
+
+// InternalEvent 描述一次背景錯誤；Path 為相關檔案或目錄，無法對應時為空字串。
+type InternalEvent struct {
+	Kind InternalEventKind
+	Path string
+	Err  error
+}
+
+// ErrorHandler 接收檔案輸出的內部錯誤。
+//
+// handler 可能由寫入端或背景 goroutine 並行呼叫，呼叫時不持有輸出的 mutex；
+// 但不應再寫入發生錯誤的同一個輸出，以免錯誤持續遞迴。handler panic 會被攔截。
+type ErrorHandler func(InternalEvent)
+
+// WithErrorHandler 設定檔案輸出內部錯誤的處理方式。
+//
+// 未設定時，換檔、清理、壓縮與 hook 錯誤寫入 stderr；寫入、同步與關閉錯誤已回傳給
+// 呼叫端，不另外輸出。設定後所有種類的事件都交給 handler；NewWithOptions 建立的 logger
+// 另將 zap 的內部錯誤（例如 console 輸出寫入失敗）以 InternalEventWrite 交給 handler，
+// 不再寫入 stderr，檔案輸出已回報的寫入錯誤也不會重複回報。
+func WithErrorHandler(handler ErrorHandler) FileOutputOption {
+	return fileOutputOptionFunc(func(settings *fileOutputSettings) error {
+		if handler == nil {
+			return fmt.Errorf("%w: ErrorHandler 不可為 nil", ErrInvalidConfig)
+		}
+		settings.errorHandler = handler
+		return nil
+	})
+}
+
+// reportInternalEvent 將事件交給 handler；err 為 nil 時略過。
+func (s fileOutputSettings) reportInternalEvent(kind InternalEventKind, path string, err error) {
+	if err == nil {
+		return
+	}
+	event := InternalEvent{Kind: kind, Path: path, Err: err}
+	if s.errorHandler == nil {
+		defaultErrorHandler(event)
+		return
+	}
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			fmt.Fprintf(os.Stderr, "zlogger ErrorHandler panic：%v（原始事件 %s %q：%v）\n",
+				recovered, event.Kind, event.Path, event.Err)
+		}
+	}()
+	s.errorHandler(event)
+}
+
+func defaultErrorHandler(event InternalEvent) {
+	switch event.Kind {
+	case InternalEventWrite, InternalEventSync, InternalEventClose:
+		return
+	}
+	fmt.Fprintf(os.Stderr, "zlogger %s 失敗 %q：%v\n", event.Kind, event.Path, event.Err)
+}
+
+// zapWriteError 回傳交給 zap 的寫入錯誤。設定 handler 時錯誤已由輸出回報，回傳 nil 避免
+// zap 經 ErrorOutput 重複回報；未設定時交由 zap 寫入 stderr。
+func (s fileOutputSettings) zapWriteError(err error) error {
+	if s.errorHandler != nil {
+		return nil
+	}
+	return err
+}
+
+// errorOutput 回傳 zap 的 ErrorOutput：設定 handler 時逐行轉為 InternalEventWrite，
+// 否則與 zap 預設相同寫入 stderr。
+func (s fileOutputSettings) errorOutput() zapcore.WriteSyncer {
+	if s.errorHandler == nil {
+		return zapcore.Lock(os.Stderr)
+	}
+	return zapcore.AddSync(errorHandlerOutput{settings: s})
+}
+
+// errorHandlerOutput 將 zap 寫入 ErrorOutput 的每一行交給 ErrorHandler。
+type errorHandlerOutput struct {
+	settings fileOutputSettings
+}
+
+func (o errorHandlerOutput) Write(data []byte) (int, error) {
+	for line := range strings.Lines(string(data)) {
+		if line = strings.TrimSpace(line); line != "" {
+			o.settings.reportInternalEvent(InternalEventWrite, "", errors.New(line))
+		}
+	}
+	return len(data), nil
+}
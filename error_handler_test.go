@@ -0,0 +1,226 @@
+package zlogger
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+type recordingErrorHandler struct {
+	mu     sync.Mutex
+	events []InternalEvent
+}
+
+func (r *recordingErrorHandler) handle(event InternalEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *recordingErrorHandler) snapshot() []InternalEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.events)
+}
+
+func TestWithErrorHandlerRejectsNil(t *testing.T) {
+	base := filepath.Join(t.TempDir(), "不應建立")
+	output, err := NewSplitOutputWithOptions(base, "app", WithErrorHandler(nil))
+	if output != nil {
+		_ = output.Close()
+		t.Fatal("nil ErrorHandler 不應回傳 SplitOutput")
+	}
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("錯誤 = %v，預期 ErrInvalidConfig", err)
+	}
+	assertPathDoesNotExist(t, base)
+}
+
+func TestSplitOutputReportsIOFailures(t *testing.T) {
+	writeErr := errors.New("info 寫入失敗")
+	syncErr := errors.New("warn 同步失敗")
+	closeErr := errors.New("error 關閉失敗")
+	handler := &recordingErrorHandler{}
+	so := &SplitOutput{
+		directory: "logs",
+		routes:    DefaultSplitRoutes(),
+		leaves:    []string{"app-info.log", "app-warn.log", "app-error.log"},
+		outputs: splitFileSet{
+			&recordingWriteSyncCloser{writeErr: writeErr},
+			&recordingWriteSyncCloser{syncErr: syncErr},
+			&recordingWriteSyncCloser{closeErr: closeErr},
+		},
+	}
+	so.settings.errorHandler = func(event InternalEvent) {
+		handler.handle(event)
+		if event.Kind == InternalEventWrite {
+			// 回報時不得持有 mutex，否則 handler 內再次使用輸出會死結。
+			_ = so.syncLevel(zapcore.ErrorLevel)
+		}
+	}
+
+	if _, err := so.Write(zapcore.InfoLevel, []byte("x\n")); !errors.Is(err, writeErr) {
+		t.Fatalf("Write 錯誤 = %v，預期 %v", err, writeErr)
+	}
+	if err := so.Sync(); !errors.Is(err, syncErr) {
+		t.Fatalf("Sync 錯誤 = %v，預期 %v", err, syncErr)
+	}
+	if err := so.Close(); !errors.Is(err, closeErr) {
+		t.Fatalf("Close 錯誤 = %v，預期 %v", err, closeErr)
+	}
+
+	want := []InternalEvent{
+		{Kind: InternalEventWrite, Path: filepath.Join("logs", "app-info.log"), Err: writeErr},
+		{Kind: InternalEventSync, Path: filepath.Join("logs", "app-warn.log"), Err: syncErr},
+		{Kind: InternalEventClose, Path: filepath.Join("logs", "app-error.log"), Err: closeErr},
+	}
+	if got := handler.snapshot(); !slices.Equal(got, want) {
+		t.Fatalf("事件 = %v，預期 %v", got, want)
+	}
+}
+
+func TestSplitOutputReportsRotationFailure(t *testing.T) {
+	base := t.TempDir()
+	openErr := errors.New("開檔失敗")
+	events := make(chan InternalEvent, 1)
+	clock := newManualRotationClock(time.Date(2026, time.July, 29, 23, 0, 0, 0, time.Local))
+	settings, err := resolveFileOutputOptions(WithErrorHandler(func(event InternalEvent) { events <- event }))
+	if err != nil {
+		t.Fatalf("解析 options 失敗：%v", err)
+	}
+	var calls int
+	opener := func(directory string, leaves []string, filePerm os.FileMode) (splitFileSet, error) {
+		calls++
+		if calls > 1 {
+			return nil, openErr
+		}
+		return openSplitFilesWithPermissions(directory, leaves, filePerm)
+	}
+	output, err := newSplitOutputWithSettings(base, "app", clock, opener, settings)
+	if err != nil {
+		t.Fatalf("建立 SplitOutput 失敗：%v", err)
+	}
+	t.Cleanup(func() { _ = output.Close() })
+
+	timer := clock.nextTimer(t)
+	nextDay := time.Date(2026, time.July, 30, 0, 0, 0, 0, time.Local)
+	clock.setNow(nextDay)
+	timer.fire(nextDay)
+
+	select {
+	case event := <-events:
+		if event.Kind != InternalEventRotation || event.Path != base || !errors.Is(event.Err, openErr) {
+			t.Fatalf("事件 = %+v，預期 rotation %q %v", event, base, openErr)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("等待換檔失敗事件逾時")
+	}
+	clock.nextTimer(t)
+}
+
+func TestNewWithOptionsReportsFileFailures(t *testing.T) {
+	base := t.TempDir()
+	handler := &recordingErrorHandler{}
+	instance, err := NewWithOptions(fileOutputTestConfig(base, "app.log"), WithErrorHandler(handler.handle))
+	if err != nil {
+		t.Fatalf("建立 Instance 失敗：%v", err)
+	}
+	file, ok := instance.closers[0].(*fixedLogFile)
+	if !ok {
+		t.Fatalf("固定檔名輸出應回報錯誤，實際為 %T", instance.closers[0])
+	}
+	if err := file.file.Close(); err != nil {
+		t.Fatalf("關閉底層檔案失敗：%v", err)
+	}
+
+	instance.Logger().Info("寫入已關閉的檔案")
+	if err := instance.Close(); err == nil {
+		t.Fatal("重複關閉底層檔案應回傳錯誤")
+	}
+
+	path := filepath.Join(base, "app.log")
+	events := handler.snapshot()
+	kinds := make([]InternalEventKind, len(events))
+	for index, event := range events {
+		kinds[index] = event.Kind
+		if event.Path != path || !errors.Is(event.Err, os.ErrClosed) {
+			t.Fatalf("事件 = %+v，預期路徑 %q 與 os.ErrClosed", event, path)
+		}
+	}
+	if !slices.Equal(kinds, []InternalEventKind{InternalEventWrite, InternalEventClose}) {
+		t.Fatalf("事件種類 = %v，預期 write、close", kinds)
+	}
+}
+
+func TestErrorHandlerReceivesZapErrorOutput(t *testing.T) {
+	dir := t.TempDir()
+	stderr, err := os.Create(filepath.Join(dir, "stderr"))
+	if err != nil {
+		t.Fatalf("建立 stderr 檔案失敗：%v", err)
+	}
+	stdout, err := os.Create(filepath.Join(dir, "stdout"))
+	if err != nil {
+		t.Fatalf("建立 stdout 檔案失敗：%v", err)
+	}
+	// console 輸出寫入已關閉的 stdout 時，zap 只能經 ErrorOutput 回報。
+	if err := stdout.Close(); err != nil {
+		t.Fatalf("關閉 stdout 檔案失敗：%v", err)
+	}
+	originalStdout, originalStderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = stdout, stderr
+	t.Cleanup(func() {
+		os.Stdout, os.Stderr = originalStdout, originalStderr
+		_ = stderr.Close()
+	})
+
+	base := filepath.Join(dir, "logs")
+	cfg := fileOutputTestConfig(base, "app.log")
+	cfg.Outputs = []string{"console", "file"}
+	handler := &recordingErrorHandler{}
+	instance, err := NewWithOptions(cfg, WithErrorHandler(handler.handle))
+	if err != nil {
+		t.Fatalf("建立 Instance 失敗：%v", err)
+	}
+	file, ok := instance.closers[0].(*fixedLogFile)
+	if !ok {
+		t.Fatalf("固定檔名輸出應為 *fixedLogFile，實際為 %T", instance.closers[0])
+	}
+	if err := file.file.Close(); err != nil {
+		t.Fatalf("關閉底層檔案失敗：%v", err)
+	}
+	instance.Logger().Info("兩個輸出都失敗")
+	_ = instance.Close()
+	os.Stdout, os.Stderr = originalStdout, originalStderr
+
+	//nolint:gosec // 測試只讀取 t.TempDir 內的預期檔案。
+	if content, err := os.ReadFile(stderr.Name()); err != nil || len(content) != 0 {
+		t.Fatalf("stderr = %q（%v），設定 ErrorHandler 後應保持空白", content, err)
+	}
+	var consoleEvents, fileEvents int
+	for _, event := range handler.snapshot() {
+		if event.Kind != InternalEventWrite {
+			continue
+		}
+		switch event.Path {
+		case "":
+			consoleEvents++
+		case filepath.Join(base, "app.log"):
+			fileEvents++
+		}
+	}
+	// 建立時的 "logger initialized" 也寫入 console；檔案輸出已回報的錯誤不應經 zap 重複回報。
+	if consoleEvents != 2 || fileEvents != 1 {
+		t.Fatalf("console 事件 %d 筆、檔案事件 %d 筆，預期 2 與 1 筆：%+v", consoleEvents, fileEvents, handler.snapshot())
+	}
+}
+
+func TestReportInternalEventRecoversHandlerPanic(t *testing.T) {
+	settings := fileOutputSettings{errorHandler: func(InternalEvent) { panic("handler 故障") }}
+	settings.reportInternalEvent(InternalEventRotation, "logs", errors.New("換檔失敗"))
+}
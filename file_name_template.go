@@ -0,0 +1,369 @@
+package zlogger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultSplitFileNameTemplate = "{prefix}-{level}-{date}.log"
+	defaultFileNameTemplate      = "{date}.log"
+)
+
+type fileNameTokenKind uint8
+
+const (
+	fileNameLiteral fileNameTokenKind = iota
+	fileNamePrefix
+	fileNameLevel
+	fileNameDate
+	fileNameHostname
+	fileNamePID
+)
+
+// fileNamePart 是樣板的一段；literal 的 text 為原文，date 的 text 為 layout，
+// {date} 的 text 為空字串，表示沿用換檔週期的日期格式。
+type fileNamePart struct {
+	kind fileNameTokenKind
+	text string
+}
+
+// fileNameTemplate 是解析後的檔名樣板，每次換檔時重新 render。
+type fileNameTemplate struct {
+	source string
+	parts  []fileNamePart
+}
+
+// fileNameValues 是 render 樣板時使用的非日期值。
+type fileNameValues struct {
+	prefix   string
+	level    string
+	hostname string
+	pid      int
+}
+
+// WithFileNameTemplate 設定日誌檔名樣板。
+//
+// 可用 token 為 {prefix}、{level}、{date}、{date:layout}、{hostname} 與 {pid}；
+// {date} 沿用換檔週期的日期格式，{date:layout} 則以 Go time layout 格式化週期起點。
+// 樣板在每次換檔時 render，結果必須是單一安全 leaf name。
+//
+// SplitOutput 的樣板必須包含 {level}，以及至少一個能完整表示換檔週期的日期 token，
+// 預設為 {prefix}-{level}-{date}.log。單一檔案輸出僅在 Config.FileName 為空時套用，
+// 不支援 {prefix} 與 {level}，預設為 {date}.log。
+func WithFileNameTemplate(template string) FileOutputOption {
+	return fileOutputOptionFunc(func(settings *fileOutputSettings) error {
+		parsed, err := parseFileNameTemplate(template)
+		if err != nil {
+			return err
+		}
+		settings.fileName = parsed
+		return nil
+	})
+}
+
+func parseFileNameTemplate(source string) (*fileNameTemplate, error) {
+	if source == "" {
+		return nil, fmt.Errorf("%w: 樣板不可為空", ErrInvalidFileNameTemplate)
+	}
+
+	template := &fileNameTemplate{source: source}
+	rest := source
+	for rest != "" {
+		open := strings.IndexAny(rest, "{}")
+		if open < 0 {
+			template.parts = append(template.parts, fileNamePart{kind: fileNameLiteral, text: rest})
+			break
+		}
+		if rest[open] == '}' {
+			return nil, fmt.Errorf("%w: 樣板 %q 含有未配對的 }", ErrInvalidFileNameTemplate, source)
+		}
+		if open > 0 {
+			template.parts = append(template.parts, fileNamePart{kind: fileNameLiteral, text: rest[:open]})
+		}
+		length := strings.IndexByte(rest[open:], '}')
+		if length < 0 {
+			return nil, fmt.Errorf("%w: 樣板 %q 含有未關閉的 {", ErrInvalidFileNameTemplate, source)
+		}
+		part, err := parseFileNameToken(rest[open+1 : open+length])
+		if err != nil {
+			return nil, fmt.Errorf("%w: 樣板 %q: %w", ErrInvalidFileNameTemplate, source, err)
+		}
+		template.parts = append(template.parts, part)
+		rest = rest[open+length+1:]
+	}
+
+	// 以固定值確認樣板本身不會產生路徑或空字串；實際值於每次 render 後再次驗證。
+	sample := template.render(
+		fileNameValues{prefix: "app", level: "info", hostname: "host", pid: 1},
+		time.Date(2026, time.January, 7, 0, 0, 0, 0, time.UTC),
+		dailyRotationLayout,
+	)
+	if err := validateLogLeaf(sample, false); err != nil {
+		return nil, fmt.Errorf("%w: 樣板 %q: %w", ErrInvalidFileNameTemplate, source, err)
+	}
+	return template, nil
+}
+
+func parseFileNameToken(token string) (fileNamePart, error) {
+	if strings.ContainsRune(token, '{') {
+		return fileNamePart{}, fmt.Errorf("token {%s 不可巢狀", token)
+	}
+	name, layout, hasLayout := strings.Cut(token, ":")
+	var kind fileNameTokenKind
+	switch name {
+	case "prefix":
+		kind = fileNamePrefix
+	case "level":
+		kind = fileNameLevel
+	case "hostname":
+		kind = fileNameHostname
+	case "pid":
+		kind = fileNamePID
+	case "date":
+		if hasLayout && layout == "" {
+			return fileNamePart{}, fmt.Errorf("token {%s} 的日期格式不可為空", token)
+		}
+		return fileNamePart{kind: fileNameDate, text: layout}, nil
+	default:
+		return fileNamePart{}, fmt.Errorf("未知 token {%s}", token)
+	}
+	if hasLayout {
+		return fileNamePart{}, fmt.Errorf("token {%s} 不接受參數", name)
+	}
+	return fileNamePart{kind: kind}, nil
+}
+
+func (t *fileNameTemplate) uses(kind fileNameTokenKind) bool {
+	for _, part := range t.parts {
+		if part.kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// values 取得 render 所需的 hostname 與 pid；只有樣板使用 {hostname} 時才查詢主機名稱。
+func (t *fileNameTemplate) values(prefix string) (fileNameValues, error) {
+	values := fileNameValues{prefix: prefix, pid: os.Getpid()}
+	if t.uses(fileNameHostname) {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return fileNameValues{}, fmt.Errorf("取得 hostname: %w", err)
+		}
+		values.hostname = hostname
+	}
+	return values, nil
+}
+
+// render 以週期起點 period 產生檔名，不做安全驗證。
+func (t *fileNameTemplate) render(values fileNameValues, period time.Time, scheduleLayout string) string {
+	var name strings.Builder
+	for _, part := range t.parts {
+		switch part.kind {
+		case fileNamePrefix:
+			name.WriteString(values.prefix)
+		case fileNameLevel:
+			name.WriteString(values.level)
+		case fileNameDate:
+			name.WriteString(period.Format(resolveDateLayout(part.text, scheduleLayout)))
+		case fileNameHostname:
+			name.WriteString(values.hostname)
+		case fileNamePID:
+			name.WriteString(strconv.Itoa(values.pid))
+		default:
+			name.WriteString(part.text)
+		}
+	}
+	return name.String()
+}
+
+// renderLeaf render 並確認結果為單一安全 leaf name。
+func (t *fileNameTemplate) renderLeaf(
+	values fileNameValues,
+	period time.Time,
+	scheduleLayout string,
+) (string, error) {
+	leaf := t.render(values, period, scheduleLayout)
+	if err := validateLogLeaf(leaf, false); err != nil {
+		return "", fmt.Errorf("日誌檔名樣板 %q: %w", t.source, err)
+	}
+	return leaf, nil
+}
+
+func resolveDateLayout(layout, scheduleLayout string) string {
+	if layout == "" {
+		return scheduleLayout
+	}
+	return layout
+}
+
+// validateSplit 確認樣板可用於 SplitOutput：各 route 檔名不同，且檔名能還原換檔週期。
+//
+// 回傳第一個能完整表示週期起點的日期 token 位置，供保留政策解析既有檔案。
+func (t *fileNameTemplate) validateSplit(schedule RotationSchedule, routeCount int) (int, error) {
+	if routeCount > 1 && !t.uses(fileNameLevel) {
+		return 0, fmt.Errorf("%w: 分級輸出的樣板 %q 必須包含 {level}", ErrInvalidFileNameTemplate, t.source)
+	}
+	for index, part := range t.parts {
+		if part.kind != fileNameDate {
+			continue
+		}
+		if schedule.validateLayout(resolveDateLayout(part.text, schedule.layout)) == nil {
+			return index, nil
+		}
+	}
+	return 0, fmt.Errorf(
+		"%w: 分級輸出的樣板 %q 必須包含能完整表示換檔週期的日期 token",
+		ErrInvalidFileNameTemplate,
+		t.source,
+	)
+}
+
+// validateSingle 確認樣板可用於沒有 prefix 與級別的單一檔案輸出。
+func (t *fileNameTemplate) validateSingle() error {
+	if t.uses(fileNamePrefix) || t.uses(fileNameLevel) {
+		return fmt.Errorf(
+			"%w: 單一檔案輸出的樣板 %q 不支援 {prefix} 與 {level}",
+			ErrInvalidFileNameTemplate,
+			t.source,
+		)
+	}
+	return nil
+}
+
+// splitLeafMatcher 辨識由同一樣板、prefix 與主機產生的分級檔，包含 -N 備份與 .gz 壓縮檔。
+type splitLeafMatcher struct {
+	template   *fileNameTemplate
+	values     fileNameValues
+	schedule   RotationSchedule
+	location   *time.Location
+	pattern    *regexp.Regexp
+	levelGroup int
+	pidGroup   int
+	dateGroup  int
+	dateLayout string
+}
+
+// matcher 建立解析器；levels 為各 route 名稱，dateIndex 為 validateSplit 回傳的日期 token 位置。
+func (t *fileNameTemplate) matcher(
+	values fileNameValues,
+	levels []string,
+	schedule RotationSchedule,
+	dateIndex int,
+	location *time.Location,
+) (*splitLeafMatcher, error) {
+	matcher := &splitLeafMatcher{
+		template:   t,
+		values:     values,
+		schedule:   schedule,
+		location:   location,
+		dateLayout: resolveDateLayout(t.parts[dateIndex].text, schedule.layout),
+	}
+	quoted := make([]string, len(levels))
+	for index, level := range levels {
+		quoted[index] = regexp.QuoteMeta(level)
+	}
+	levelPattern := "(?:" + strings.Join(quoted, "|") + ")"
+	if len(levels) == 1 {
+		matcher.values.level = levels[0]
+	}
+
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	group := 0
+	capture := func(expression string) int {
+		group++
+		pattern.WriteString("(" + expression + ")")
+		return group
+	}
+	for index, part := range t.parts {
+		switch {
+		case index == dateIndex:
+			matcher.dateGroup = capture(".+?")
+		case part.kind == fileNameLevel && matcher.levelGroup == 0:
+			matcher.levelGroup = capture(levelPattern)
+		case part.kind == fileNameLevel:
+			pattern.WriteString(levelPattern)
+		case part.kind == fileNamePID && matcher.pidGroup == 0:
+			matcher.pidGroup = capture("[0-9]+")
+		case part.kind == fileNamePID:
+			pattern.WriteString("[0-9]+")
+		case part.kind == fileNameDate:
+			pattern.WriteString(".+?")
+		case part.kind == fileNamePrefix:
+			pattern.WriteString(regexp.QuoteMeta(values.prefix))
+		case part.kind == fileNameHostname:
+			pattern.WriteString(regexp.QuoteMeta(values.hostname))
+		default:
+			pattern.WriteString(regexp.QuoteMeta(part.text))
+		}
+	}
+	pattern.WriteString("$")
+
+	compiled, err := regexp.Compile(pattern.String())
+	if err != nil {
+		return nil, fmt.Errorf("%w: 樣板 %q: %w", ErrInvalidFileNameTemplate, t.source, err)
+	}
+	matcher.pattern = compiled
+	return matcher, nil
+}
+
+// parse 解析目前檔名、numberedBackupLeaf 產生的 -N 備份與 .gz 壓縮檔。
+func (m *splitLeafMatcher) parse(leaf string) (retainedLogFile, bool) {
+	stem := strings.TrimSuffix(leaf, compressedLogSuffix)
+	if file, ok := m.match(stem); ok {
+		file.leaf = leaf
+		return file, true
+	}
+
+	extension := filepath.Ext(stem)
+	base := strings.TrimSuffix(stem, extension)
+	separator := strings.LastIndexByte(base, '-')
+	if separator < 0 {
+		return retainedLogFile{}, false
+	}
+	backup, ok := parseBackupIndex(base[separator+1:])
+	if !ok {
+		return retainedLogFile{}, false
+	}
+	file, ok := m.match(base[:separator] + extension)
+	if !ok {
+		return retainedLogFile{}, false
+	}
+	file.leaf = leaf
+	file.backup = backup
+	return file, true
+}
+
+// match 解析日期後重新 render，只接受與樣板輸出完全相同的檔名。
+func (m *splitLeafMatcher) match(name string) (retainedLogFile, bool) {
+	groups := m.pattern.FindStringSubmatch(name)
+	if groups == nil {
+		return retainedLogFile{}, false
+	}
+	date, err := time.ParseInLocation(m.dateLayout, groups[m.dateGroup], m.location)
+	if err != nil {
+		return retainedLogFile{}, false
+	}
+	values := m.values
+	if m.levelGroup > 0 {
+		values.level = groups[m.levelGroup]
+	}
+	if m.pidGroup > 0 {
+		pid, err := strconv.Atoi(groups[m.pidGroup])
+		if err != nil {
+			return retainedLogFile{}, false
+		}
+		values.pid = pid
+	}
+	if m.template.render(values, date, m.schedule.layout) != name {
+		return retainedLogFile{}, false
+	}
+	return retainedLogFile{level: values.level, date: date}, true
+}
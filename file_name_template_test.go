@@ -0,0 +1,115 @@
+package zlogger
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestWithFileNameTemplateRejectsInvalidTemplates(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+	}{
+		{name: "空樣板", template: ""},
+		{name: "未知 token", template: "{prefix}-{level}-{user}.log"},
+		{name: "未關閉", template: "{prefix}-{level}-{date.log"},
+		{name: "未配對", template: "{prefix}-{level}-date}.log"},
+		{name: "巢狀", template: "{prefix}-{level}-{date:{pid}}.log"},
+		{name: "token 參數", template: "{prefix:x}-{level}-{date}.log"},
+		{name: "空日期格式", template: "{prefix}-{level}-{date:}.log"},
+		{name: "路徑分隔符", template: "{prefix}/{level}-{date}.log"},
+		{name: "日期格式含路徑", template: "{prefix}-{level}-{date:2006/01/02}.log"},
+		{name: "缺少級別", template: "{prefix}-{date}.log"},
+		{name: "缺少日期", template: "{prefix}-{level}.log"},
+		{name: "日期無法表示週期", template: "{prefix}-{level}-{date:2006-01}.log"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := filepath.Join(t.TempDir(), "不應建立")
+			output, err := NewSplitOutputWithOptions(base, "app", WithFileNameTemplate(tt.template))
+			if output != nil {
+				_ = output.Close()
+				t.Fatal("無效樣板不應回傳 SplitOutput")
+			}
+			if !errors.Is(err, ErrInvalidFileNameTemplate) {
+				t.Fatalf("錯誤 = %v，預期 ErrInvalidFileNameTemplate", err)
+			}
+			assertPathDoesNotExist(t, base)
+		})
+	}
+}
+
+func TestSplitOutputFileNameTemplate(t *testing.T) {
+	hostname, err := os.Hostname()
+	if err != nil || validateLogLeaf(hostname, false) != nil {
+		t.Skipf("hostname 無法作為 leaf name：%q, %v", hostname, err)
+	}
+	base := t.TempDir()
+	now := time.Date(2026, time.July, 29, 10, 0, 0, 0, time.Local)
+	pid := strconv.Itoa(os.Getpid())
+	createRetentionFixtures(t, base,
+		hostname+".app.info.1.20260727.log",
+		hostname+".app.info.2.20260728.log.gz",
+		"other.app.info.1.20260727.log",
+		hostname+".app.info.1.2026-07-27.log",
+	)
+
+	output := newRetentionTestOutput(t, base, now,
+		WithFileNameTemplate("{hostname}.{prefix}.{level}.{pid}.{date:20060102}.log"),
+		WithMaxFiles(2),
+	)
+	if _, err := output.Write(zapcore.WarnLevel, []byte("templated\n")); err != nil {
+		t.Fatalf("寫入 warn 失敗：%v", err)
+	}
+	if err := output.Close(); err != nil {
+		t.Fatalf("關閉 SplitOutput 失敗：%v", err)
+	}
+
+	assertFileContent(t, filepath.Join(base, hostname+".app.warn."+pid+".20260729.log"), "templated\n")
+	assertRetainedLogFiles(t, base,
+		hostname+".app.error."+pid+".20260729.log",
+		hostname+".app.info."+pid+".20260729.log",
+		hostname+".app.info.1.2026-07-27.log",
+		hostname+".app.info.2.20260728.log.gz",
+		hostname+".app.warn."+pid+".20260729.log",
+		"other.app.info.1.20260727.log",
+	)
+}
+
+func TestNewWithOptionsFileNameTemplate(t *testing.T) {
+	base := t.TempDir()
+	instance, err := NewWithOptions(
+		fileOutputTestConfig(base, ""),
+		WithFileNameTemplate("service-{date:20060102}-{pid}.log"),
+		WithRotationLocation(time.UTC),
+	)
+	if err != nil {
+		t.Fatalf("以檔名樣板建立 Instance 失敗：%v", err)
+	}
+	t.Cleanup(func() { _ = instance.Close() })
+
+	leaf := "service-" + time.Now().UTC().Format("20060102") + "-" + strconv.Itoa(os.Getpid()) + ".log"
+	if _, err := os.Stat(filepath.Join(base, leaf)); err != nil {
+		t.Fatalf("預期建立 %q：%v", leaf, err)
+	}
+}
+
+func TestNewWithOptionsRejectsSplitOnlyTokens(t *testing.T) {
+	base := filepath.Join(t.TempDir(), "不應建立")
+	instance, err := NewWithOptions(fileOutputTestConfig(base, ""), WithFileNameTemplate("{level}-{date}.log"))
+	if instance != nil {
+		_ = instance.Close()
+		t.Fatal("單一檔案輸出不應接受 {level}")
+	}
+	if !errors.Is(err, ErrInvalidFileNameTemplate) {
+		t.Fatalf("錯誤 = %v，預期 ErrInvalidFileNameTemplate", err)
+	}
+	assertPathDoesNotExist(t, base)
+}
@@ -4,14 +4,23 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"time"
+
+	"go.uber.org/zap/zapcore"
 )
 
-// ErrInvalidFilePermission 表示檔案輸出建立權限不符合安全契約。
-var ErrInvalidFilePermission = errors.New("檔案輸出權限無效")
+var (
+	// ErrInvalidFilePermission 表示檔案輸出建立權限不符合安全契約。
+	ErrInvalidFilePermission = errors.New("檔案輸出權限無效")
+	// ErrInvalidRotation 表示檔案輸出的換檔設定無效。
+	ErrInvalidRotation = errors.New("日誌換檔設定無效")
+	// ErrInvalidFileNameTemplate 表示日誌檔名樣板無效。
+	ErrInvalidFileNameTemplate = errors.New("日誌檔名樣板無效")
+)
 
-// FileOutputOption 設定新建日誌目錄與檔案的權限。
+// FileOutputOption 設定檔案輸出的權限與換檔行為。
 //
-// Option 只能由本 package 提供的 WithDirPerm 與 WithFilePerm 建立。
+// Option 只能由本 package 提供的 With 系列函式建立。
 type FileOutputOption interface {
 	applyFileOutput(*fileOutputSettings) error
 }
@@ -31,6 +40,59 @@ type fileOutputOption struct {
 type fileOutputSettings struct {
 	dirPerm  os.FileMode
 	filePerm os.FileMode
+	maxSize  int64
+	maxAge   time.Duration
+	maxFiles int
+	compress bool
+	schedule RotationSchedule
+	location *time.Location
+	fileName *fileNameTemplate
+	routes   []SplitRoute
+
+	currentLink        *fileNameTemplate
+	currentLinkEnabled bool
+	rotationHooks      []RotationHook
+	errorHandler       ErrorHandler
+	checkInterval      time.Duration
+	minFreeBytes       uint64
+	lowDiskLevel       zapcore.Level
+	diskSpaceProbe     diskSpaceProbe
+	clock              rotationClock
+	bufferSize         int
+	flushInterval      time.Duration
+	asyncQueueSize     int
+	overflow           OverflowPolicy
+}
+
+// splitRoutes 回傳設定的路由表；未設定時使用 DefaultSplitRoutes。
+func (s fileOutputSettings) splitRoutes() []SplitRoute {
+	if s.routes == nil {
+		return DefaultSplitRoutes()
+	}
+	return s.routes
+}
+
+// outputClock 回傳 Instance 擁有之輸出的換檔時鐘；未設定時使用系統時間。
+func (s fileOutputSettings) outputClock() rotationClock {
+	if s.clock == nil {
+		return systemRotationClock{}
+	}
+	return s.clock
+}
+
+// fileNameTemplate 回傳設定的檔名樣板；未設定時解析 fallback。
+func (s fileOutputSettings) fileNameTemplate(fallback string) (*fileNameTemplate, error) {
+	if s.fileName != nil {
+		return s.fileName, nil
+	}
+	return parseFileNameTemplate(fallback)
+}
+
+// fileOutputOptionFunc 供非權限類 option 直接修改設定。
+type fileOutputOptionFunc func(*fileOutputSettings) error
+
+func (f fileOutputOptionFunc) applyFileOutput(settings *fileOutputSettings) error {
+	return f(settings)
 }
 
 // WithDirPerm 設定新建日誌目錄的 permission bits。
@@ -49,6 +111,61 @@ func WithFilePerm(perm os.FileMode) FileOutputOption {
 	return fileOutputOption{kind: fileOutputOptionFilePerm, perm: perm}
 }
 
+// WithMaxSize 設定單一分級日誌檔的大小上限（bytes）。
+//
+// 寫入會使目前檔案超過上限時，先將其改名為同目錄下第一個未使用的 -1、-2…編號備份，
+// 再以原檔名建立新檔繼續寫入。單筆超過上限的日誌仍完整寫入新檔。
+//
+// 適用於 NewSplitOutputWithOptions、NewWithOptions 的 split 輸出，以及 FileName 為空、
+// 依日期換檔的 file 輸出。指定 FileName 的 file 輸出固定寫入同一檔案，忽略此設定。
+func WithMaxSize(maxBytes int64) FileOutputOption {
+	return fileOutputOptionFunc(func(settings *fileOutputSettings) error {
+		if maxBytes <= 0 {
+			return fmt.Errorf("%w: MaxSize %d 必須大於 0", ErrInvalidRotation, maxBytes)
+		}
+		settings.maxSize = maxBytes
+		return nil
+	})
+}
+
+// WithMaxAge 設定分級日誌檔的保留時間。
+//
+// 檔名日期所屬週期結束已超過 maxAge 的檔案，會在啟動與每次換檔後刪除。
+// 只處理符合本 SplitOutput prefix、level 與日期格式的檔案，不刪除目前使用中的檔案。
+func WithMaxAge(maxAge time.Duration) FileOutputOption {
+	return fileOutputOptionFunc(func(settings *fileOutputSettings) error {
+		if maxAge <= 0 {
+			return fmt.Errorf("%w: MaxAge %s 必須大於 0", ErrInvalidRotation, maxAge)
+		}
+		settings.maxAge = maxAge
+		return nil
+	})
+}
+
+// WithMaxFiles 設定每個級別最多保留的日誌檔數量，包含目前使用中的檔案與編號備份。
+//
+// 超出數量的最舊檔案會在啟動與每次換檔後刪除，匹配規則與 WithMaxAge 相同。
+func WithMaxFiles(maxFiles int) FileOutputOption {
+	return fileOutputOptionFunc(func(settings *fileOutputSettings) error {
+		if maxFiles <= 0 {
+			return fmt.Errorf("%w: MaxFiles %d 必須大於 0", ErrInvalidRotation, maxFiles)
+		}
+		settings.maxFiles = maxFiles
+		return nil
+	})
+}
+
+// WithCompression 啟用換檔後的背景 gzip 壓縮。
+//
+// 換日或依大小換檔後，已關閉的檔案會在背景壓縮為 .log.gz，並沿用 WithFilePerm
+// 的檔案權限；壓縮檔原子改名完成後才刪除原檔。SplitOutput.Close 會等待已排入的壓縮完成。
+func WithCompression() FileOutputOption {
+	return fileOutputOptionFunc(func(settings *fileOutputSettings) error {
+		settings.compress = true
+		return nil
+	})
+}
+
 func (o fileOutputOption) applyFileOutput(settings *fileOutputSettings) error {
 	switch o.kind {
 	case fileOutputOptionDirPerm:
@@ -71,6 +188,7 @@ func resolveFileOutputOptions(opts ...FileOutputOption) (fileOutputSettings, err
 	settings := fileOutputSettings{
 		dirPerm:  defaultLogDirMode,
 		filePerm: defaultLogFileMode,
+		schedule: DailyRotation(),
 	}
 	if err := validateFileOutputPermission("目錄", settings.dirPerm, 0o700); err != nil {
 		return fileOutputSettings{}, err
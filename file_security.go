@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 )
 
@@ -166,3 +167,130 @@ func closeRootedLogFiles(files []*os.File) error {
 	}
 	return errors.Join(closeErrs...)
 }
+
+// withLogRoot 開啟 baseDir 的 os.Root 執行 operate，並保留 root 關閉錯誤。
+func withLogRoot(baseDir string, operate func(*os.Root) error) error {
+	root, err := os.OpenRoot(baseDir)
+	if err != nil {
+		return fmt.Errorf("開啟日誌 root %q: %w", baseDir, err)
+	}
+
+	err = operate(root)
+	if closeErr := root.Close(); closeErr != nil {
+		err = errors.Join(err, fmt.Errorf("關閉日誌 root %q: %w", baseDir, closeErr))
+	}
+	return err
+}
+
+// rollRootedLogFile 在 baseDir 的 os.Root 內將 leaf 改名為第一個未使用的編號備份。
+//
+// 既存 leaf 為 symlink 時拒絕改名；回傳值為備份的 leaf name。
+func rollRootedLogFile(baseDir, leaf string) (string, error) {
+	if err := validateLogLeaf(leaf, false); err != nil {
+		return "", err
+	}
+
+	var backup string
+	err := withLogRoot(baseDir, func(root *os.Root) error {
+		var renameErr error
+		backup, renameErr = renameToNumberedBackup(root, baseDir, leaf)
+		return renameErr
+	})
+	if err != nil {
+		return "", err
+	}
+	return backup, nil
+}
+
+func renameToNumberedBackup(root *os.Root, baseDir, leaf string) (string, error) {
+	info, err := root.Lstat(leaf)
+	if err != nil {
+		return "", fmt.Errorf("檢查日誌 root %q 的 leaf %q: %w", baseDir, leaf, err)
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		return "", fmt.Errorf("%w: 日誌 root %q 的 leaf %q 是 symlink", ErrUnsafeLogPath, baseDir, leaf)
+	}
+
+	for index := 1; ; index++ {
+		backup := numberedBackupLeaf(leaf, index)
+		if err := validateLogLeaf(backup, false); err != nil {
+			return "", err
+		}
+		used, err := rootedLeafExists(root, baseDir, backup, backup+compressedLogSuffix)
+		if err != nil {
+			return "", err
+		}
+		if used {
+			continue
+		}
+		if err := root.Rename(leaf, backup); err != nil {
+			return "", fmt.Errorf("改名日誌 root %q 的 leaf %q 為 %q: %w", baseDir, leaf, backup, err)
+		}
+		return backup, nil
+	}
+}
+
+// rootedLeafExists 回傳任一 leaf 是否已存在；已壓縮的備份同樣佔用編號。
+func rootedLeafExists(root *os.Root, baseDir string, leaves ...string) (bool, error) {
+	for _, leaf := range leaves {
+		_, err := root.Lstat(leaf)
+		if err == nil {
+			return true, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return false, fmt.Errorf("檢查日誌 root %q 的 leaf %q: %w", baseDir, leaf, err)
+		}
+	}
+	return false, nil
+}
+
+// numberedBackupLeaf 在副檔名前插入 -index，例如 app-info-2026-07-29-1.log。
+func numberedBackupLeaf(leaf string, index int) string {
+	extension := filepath.Ext(leaf)
+	return strings.TrimSuffix(leaf, extension) + "-" + strconv.Itoa(index) + extension
+}
+
+// listRootedRegularFiles 回傳 root 目錄下的一般檔案 leaf name，不包含 symlink 與子目錄。
+func listRootedRegularFiles(root *os.Root, baseDir string) ([]string, error) {
+	directory, err := root.Open(".")
+	if err != nil {
+		return nil, fmt.Errorf("開啟日誌 root %q 目錄: %w", baseDir, err)
+	}
+	entries, err := directory.ReadDir(-1)
+	if closeErr := directory.Close(); closeErr != nil {
+		err = errors.Join(err, fmt.Errorf("關閉日誌 root %q 目錄: %w", baseDir, closeErr))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("讀取日誌 root %q 目錄: %w", baseDir, err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Type().IsRegular() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+// removeRootedLogFile 在 root 內刪除 leaf；已不存在時視為成功，symlink 與非一般檔案一律拒絕。
+func removeRootedLogFile(root *os.Root, baseDir, leaf string) error {
+	if err := validateLogLeaf(leaf, false); err != nil {
+		return err
+	}
+
+	info, err := root.Lstat(leaf)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("檢查日誌 root %q 的 leaf %q: %w", baseDir, leaf, err)
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%w: 日誌 root %q 的 leaf %q 不是一般檔案", ErrUnsafeLogPath, baseDir, leaf)
+	}
+	if err := root.Remove(leaf); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("刪除日誌 root %q 的 leaf %q: %w", baseDir, leaf, err)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package zlogger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// ErrLogFileReplaced 表示開啟中的日誌檔已被刪除、移動或替換，路徑不再指向目前 handle。
+var ErrLogFileReplaced = errors.New("日誌檔案已被刪除或移動")
+
+// WithFileCheckInterval 啟用定期檢查開啟中的檔案是否仍位於設定路徑。
+//
+// 每隔 interval 比對目前 handle 與 os.Root 內 Lstat 取得的檔案識別；檔案被刪除、移動或
+// 替換時自動 Reopen，並以 InternalEventReopen 與包裝 ErrLogFileReplaced 的錯誤交給
+// ErrorHandler。只適用於 SplitOutput 與未指定 FileName 的 file output。
+func WithFileCheckInterval(interval time.Duration) FileOutputOption {
+	return fileOutputOptionFunc(func(settings *fileOutputSettings) error {
+		if interval <= 0 {
+			return fmt.Errorf("%w: 檔案檢查間隔必須大於 0", ErrInvalidRotation)
+		}
+		settings.checkInterval = interval
+		return nil
+	})
+}
+
+// watchFiles 定期檢查檔案識別，直到 Close 關閉 stop。
+func (s *SplitOutput) watchFiles() {
+	defer close(s.watchDone)
+	runEvery(s.clock, s.settings.checkInterval, s.stop, s.checkFiles)
+}
+
+// checkFiles 在任一檔案的路徑不再指向目前 handle 時重新開啟所有檔案。
+func (s *SplitOutput) checkFiles() {
+	s.mutex.Lock()
+	if s.closed {
+		s.mutex.Unlock()
+		return
+	}
+	leaves := slices.Clone(s.leaves)
+	opened := make([]os.FileInfo, len(s.outputs))
+	for index, file := range s.outputs {
+		if stater, ok := file.(fileStater); ok {
+			opened[index], _ = stater.Stat()
+		}
+	}
+	s.mutex.Unlock()
+
+	var replaced []string
+	err := withLogRoot(s.directory, func(root *os.Root) error {
+		for index, leaf := range leaves {
+			if index >= len(opened) || opened[index] == nil {
+				continue
+			}
+			current, err := root.Lstat(leaf)
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("檢查日誌 root %q 的 leaf %q: %w", s.directory, leaf, err)
+			}
+			if err != nil || !os.SameFile(opened[index], current) {
+				replaced = append(replaced, leaf)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.settings.reportInternalEvent(InternalEventReopen, s.directory, err)
+		return
+	}
+	if len(replaced) == 0 {
+		return
+	}
+
+	reopenErr := s.Reopen()
+	if errors.Is(reopenErr, os.ErrClosed) {
+		return
+	}
+	for _, leaf := range replaced {
+		s.settings.reportInternalEvent(
+			InternalEventReopen,
+			filepath.Join(s.directory, leaf),
+			errors.Join(ErrLogFileReplaced, reopenErr),
+		)
+	}
+}
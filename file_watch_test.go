@@ -0,0 +1,93 @@
+package zlogger
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestWithFileCheckIntervalRejectsNonPositive(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Second} {
+		base := filepath.Join(t.TempDir(), "不應建立")
+		output, err := NewSplitOutputWithOptions(base, "app", WithFileCheckInterval(interval))
+		if output != nil {
+			_ = output.Close()
+			t.Fatalf("間隔 %s 不應回傳 SplitOutput", interval)
+		}
+		if !errors.Is(err, ErrInvalidRotation) {
+			t.Fatalf("錯誤 = %v，預期 ErrInvalidRotation", err)
+		}
+		assertPathDoesNotExist(t, base)
+	}
+}
+
+func TestSplitOutputCheckFilesReopensDeletedFile(t *testing.T) {
+	base := t.TempDir()
+	handler := &recordingErrorHandler{}
+	output := newRetentionTestOutput(t, base, time.Date(2026, time.July, 29, 10, 0, 0, 0, time.Local),
+		WithErrorHandler(handler.handle),
+	)
+	t.Cleanup(func() { _ = output.Close() })
+
+	output.checkFiles()
+	if events := handler.snapshot(); len(events) != 0 {
+		t.Fatalf("檔案未變動時不應回報事件：%v", events)
+	}
+
+	active := filepath.Join(base, "app-info-2026-07-29.log")
+	if err := os.Remove(active); err != nil {
+		t.Fatalf("刪除使用中的檔案失敗：%v", err)
+	}
+	output.checkFiles()
+
+	events := handler.snapshot()
+	if len(events) != 1 || events[0].Kind != InternalEventReopen || events[0].Path != active ||
+		!errors.Is(events[0].Err, ErrLogFileReplaced) {
+		t.Fatalf("事件 = %+v，預期 %q 的 reopen 事件", events, active)
+	}
+	if _, err := output.Write(zapcore.InfoLevel, []byte("重新開啟後\n")); err != nil {
+		t.Fatalf("寫入失敗：%v", err)
+	}
+	if err := output.Sync(); err != nil {
+		t.Fatalf("同步失敗：%v", err)
+	}
+	assertFileContent(t, active, "重新開啟後\n")
+}
+
+func TestSplitOutputFileCheckRecoversMovedFile(t *testing.T) {
+	base := t.TempDir()
+	events := make(chan InternalEvent, 4)
+	output, err := NewSplitOutputWithOptions(
+		base,
+		"app",
+		WithFileCheckInterval(10*time.Millisecond),
+		WithErrorHandler(func(event InternalEvent) { events <- event }),
+	)
+	if err != nil {
+		t.Fatalf("建立 SplitOutput 失敗：%v", err)
+	}
+	t.Cleanup(func() { _ = output.Close() })
+
+	active := filepath.Join(base, "app-warn-"+output.now().Format(dailyRotationLayout)+".log")
+	if err := os.Rename(active, active+".moved"); err != nil {
+		t.Fatalf("移動使用中的檔案失敗：%v", err)
+	}
+	select {
+	case event := <-events:
+		if event.Kind != InternalEventReopen || event.Path != active || !errors.Is(event.Err, ErrLogFileReplaced) {
+			t.Fatalf("事件 = %+v，預期 %q 的 reopen 事件", event, active)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("等待檔案檢查逾時")
+	}
+	if _, err := os.Stat(active); err != nil {
+		t.Fatalf("檢查後應重新建立 %s：%v", active, err)
+	}
+	if err := output.Close(); err != nil {
+		t.Fatalf("關閉 SplitOutput 失敗：%v", err)
+	}
+}
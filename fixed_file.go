@@ -0,0 +1,145 @@
+package zlogger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// fixedLogFile 是指定 FileName 時的 file output，將 I/O 錯誤回報給 ErrorHandler。
+//
+// 寫入、同步、Reopen 與 Close 以 mutex 序列化；回報錯誤時已釋放 mutex。
+// 啟用 WithBufferedWrites 時另有 goroutine 定期寫出緩衝，Close 會等待其結束。
+type fixedLogFile struct {
+	directory string
+	leaf      string
+	settings  fileOutputSettings
+
+	mu     sync.Mutex
+	file   writeSyncCloser
+	closed bool
+
+	stop      chan struct{}
+	stopOnce  sync.Once
+	flushDone chan struct{}
+}
+
+func openFixedLogFile(directory, leaf string, settings fileOutputSettings) (*fixedLogFile, error) {
+	file, err := openSingleRootedLogFile(directory, leaf, settings.filePerm)
+	if err != nil {
+		return nil, err
+	}
+	logFile := &fixedLogFile{directory: directory, leaf: leaf, settings: settings}
+	logFile.file = logFile.withBuffer(file)
+	if settings.bufferSize > 0 {
+		logFile.stop = make(chan struct{})
+		logFile.flushDone = make(chan struct{})
+		go func() {
+			defer close(logFile.flushDone)
+			runEvery(systemRotationClock{}, settings.flushInterval, logFile.stop, logFile.flush)
+		}()
+	}
+	return logFile, nil
+}
+
+// withBuffer 依設定為新開啟的 handle 套用寫入緩衝。
+func (f *fixedLogFile) withBuffer(file *os.File) writeSyncCloser {
+	if f.settings.bufferSize > 0 {
+		return newBufferedFile(file, f.settings.bufferSize)
+	}
+	return file
+}
+
+// flush 寫出緩衝；已關閉時略過。
+func (f *fixedLogFile) flush() {
+	f.mu.Lock()
+	if f.closed {
+		f.mu.Unlock()
+		return
+	}
+	err := flushFile(f.file)
+	f.mu.Unlock()
+
+	f.settings.reportInternalEvent(InternalEventWrite, f.path(), err)
+}
+
+func openSingleRootedLogFile(directory, leaf string, filePerm os.FileMode) (*os.File, error) {
+	files, err := openRootedLogFilesWithPermissions(directory, filePerm, leaf)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) != 1 {
+		return nil, errors.Join(
+			fmt.Errorf("取得日誌檔案數量 %d，預期 1: %w", len(files), os.ErrInvalid),
+			closeRootedLogFiles(files),
+		)
+	}
+	return files[0], nil
+}
+
+func (f *fixedLogFile) path() string {
+	return filepath.Join(f.directory, f.leaf)
+}
+
+func (f *fixedLogFile) Write(data []byte) (int, error) {
+	f.mu.Lock()
+	written, err := f.file.Write(data)
+	f.mu.Unlock()
+
+	f.settings.reportInternalEvent(InternalEventWrite, f.path(), err)
+	return written, f.settings.zapWriteError(err)
+}
+
+func (f *fixedLogFile) Sync() error {
+	f.mu.Lock()
+	err := f.file.Sync()
+	f.mu.Unlock()
+
+	f.settings.reportInternalEvent(InternalEventSync, f.path(), err)
+	return err
+}
+
+// Reopen 以相同路徑重新開啟檔案並替換 handle；開啟失敗時沿用原 handle。
+func (f *fixedLogFile) Reopen() error {
+	f.mu.Lock()
+	if f.closed {
+		f.mu.Unlock()
+		return fmt.Errorf("重新開啟日誌 %q: %w", f.path(), os.ErrClosed)
+	}
+	f.mu.Unlock()
+
+	file, err := openSingleRootedLogFile(f.directory, f.leaf, f.settings.filePerm)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	if f.closed {
+		f.mu.Unlock()
+		return errors.Join(fmt.Errorf("重新開啟日誌 %q: %w", f.path(), os.ErrClosed), file.Close())
+	}
+	previous := f.file
+	f.file = f.withBuffer(file)
+	f.mu.Unlock()
+
+	err = previous.Close()
+	f.settings.reportInternalEvent(InternalEventClose, f.path(), err)
+	return nil
+}
+
+func (f *fixedLogFile) Close() error {
+	if f.stop != nil {
+		f.stopOnce.Do(func() { close(f.stop) })
+		<-f.flushDone
+	}
+
+	f.mu.Lock()
+	f.closed = true
+	err := f.file.Close()
+	f.mu.Unlock()
+
+	f.settings.reportInternalEvent(InternalEventClose, f.path(), err)
+	return err
+}
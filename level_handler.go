@@ -0,0 +1,174 @@
+package zlogger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// levelHandlerMaxBody 是級別變更請求 body 的上限。
+const levelHandlerMaxBody = 64 << 10
+
+// levelState 是 GET 與成功變更後回傳的 JSON。
+type levelState struct {
+	Level     string            `json:"level"`
+	Overrides map[string]string `json:"overrides"`
+	Spec      string            `json:"spec"`
+	ExpiresAt *time.Time        `json:"expires_at,omitempty"`
+}
+
+// levelChange 是 PUT 與 POST 接受的 JSON；未提供的欄位保留目前值。
+type levelChange struct {
+	Level     *string            `json:"level"`
+	Overrides *map[string]string `json:"overrides"`
+	ExpiresIn string             `json:"expires_in"`
+}
+
+type levelError struct {
+	Error string `json:"error"`
+}
+
+// levelHandler 以 resolve 取得目前的 nameLevels；回傳 nil 表示 logger 尚未設定。
+type levelHandler struct {
+	resolve func() *nameLevels
+}
+
+// LevelHandler 回傳檢視與調整全域 logger 級別的 http.Handler。
+//
+// 請求時才取得目前的全域 logger，尚未設定時回應 503。JSON 格式與 Instance.LevelHandler 相同。
+func LevelHandler() http.Handler {
+	return &levelHandler{resolve: func() *nameLevels {
+		configureMu.Lock()
+		defer configureMu.Unlock()
+		return globalLevels
+	}}
+}
+
+// LevelHandler 回傳檢視與調整此 Instance 級別的 http.Handler。
+//
+// GET 回傳 {"level","overrides","spec","expires_at"}。PUT 或 POST 接受
+// {"level":"debug","overrides":{"db":"debug"},"expires_in":"10m"}：level 替換基礎級別，
+// overrides 存在時整批替換名稱覆寫，expires_in 為 time.ParseDuration 格式，到期後恢復
+// 變更前的規格。未知欄位與未知級別回應 400，不會回退 info。handler 不做驗證授權，
+// 應只掛載於受保護的管理端點。nil Instance 的 handler 與尚未設定的全域 handler 相同，回應 503。
+func (i *Instance) LevelHandler() http.Handler {
+	return &levelHandler{resolve: func() *nameLevels {
+		if i == nil {
+			return nil
+		}
+		return i.levels
+	}}
+}
+
+func (h *levelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	levels := h.resolve()
+	if levels == nil {
+		writeLevelJSON(w, http.StatusServiceUnavailable, levelError{Error: ErrNotConfigured.Error()})
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		spec, expiresAt := levels.state()
+		writeLevelJSON(w, http.StatusOK, newLevelState(spec, expiresAt))
+	case http.MethodPut, http.MethodPost:
+		merge, expiry, err := decodeLevelChange(r)
+		if err != nil {
+			status := http.StatusBadRequest
+			if errors.As(err, new(*http.MaxBytesError)) {
+				status = http.StatusRequestEntityTooLarge
+			}
+			writeLevelJSON(w, status, levelError{Error: err.Error()})
+			return
+		}
+		spec, expiresAt := levels.update(merge, expiry)
+		writeLevelJSON(w, http.StatusOK, newLevelState(spec, expiresAt))
+	default:
+		w.Header().Set("Allow", "GET, HEAD, PUT, POST")
+		writeLevelJSON(w, http.StatusMethodNotAllowed, levelError{Error: fmt.Sprintf("不支援的方法 %s", r.Method)})
+	}
+}
+
+// decodeLevelChange 解析並驗證請求，回傳以目前規格補齊未提供欄位的 merge。
+func decodeLevelChange(r *http.Request) (func(levelSpec) levelSpec, time.Duration, error) {
+	var change levelChange
+	decoder := json.NewDecoder(http.MaxBytesReader(nil, r.Body, levelHandlerMaxBody))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&change); err != nil {
+		return nil, 0, fmt.Errorf("解析級別變更: %w", err)
+	}
+	if err := decoder.Decode(new(json.RawMessage)); !errors.Is(err, io.EOF) {
+		return nil, 0, fmt.Errorf("%w: 級別變更只能包含一個 JSON 物件", ErrInvalidConfig)
+	}
+	if change.Level == nil && change.Overrides == nil {
+		return nil, 0, fmt.Errorf("%w: 級別變更至少需要 level 或 overrides", ErrInvalidConfig)
+	}
+
+	var base *zapcore.Level
+	if change.Level != nil {
+		level, err := parseStrictLevel(*change.Level)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%w: level: %w", ErrInvalidConfig, err)
+		}
+		base = &level
+	}
+	var overrides map[string]zapcore.Level
+	if change.Overrides != nil {
+		overrides = make(map[string]zapcore.Level, len(*change.Overrides))
+		for name, levelText := range *change.Overrides {
+			if name == "" || strings.ContainsAny(name, ",= \t") {
+				return nil, 0, fmt.Errorf("%w: logger 名稱 %q 無效", ErrInvalidConfig, name)
+			}
+			level, err := parseStrictLevel(levelText)
+			if err != nil {
+				return nil, 0, fmt.Errorf("%w: overrides[%q]: %w", ErrInvalidConfig, name, err)
+			}
+			overrides[name] = level
+		}
+	}
+
+	var expiry time.Duration
+	if change.ExpiresIn != "" {
+		parsed, err := time.ParseDuration(change.ExpiresIn)
+		if err != nil || parsed <= 0 {
+			return nil, 0, fmt.Errorf("%w: expires_in %q 必須是正的 duration", ErrInvalidConfig, change.ExpiresIn)
+		}
+		expiry = parsed
+	}
+	merge := func(spec levelSpec) levelSpec {
+		if base != nil {
+			spec.base = *base
+		}
+		if overrides != nil {
+			spec.overrides = overrides
+		}
+		return spec
+	}
+	return merge, expiry, nil
+}
+
+func newLevelState(spec levelSpec, expiresAt time.Time) levelState {
+	overrides := make(map[string]string, len(spec.overrides))
+	for name, level := range maps.All(spec.overrides) {
+		overrides[name] = level.String()
+	}
+	state := levelState{Level: spec.base.String(), Overrides: overrides, Spec: spec.String()}
+	if !expiresAt.IsZero() {
+		state.ExpiresAt = &expiresAt
+	}
+	return state
+}
+
+func writeLevelJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,168 @@
+package zlogger
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func serveLevelRequest(t *testing.T, handler http.Handler, method, body string) (int, levelState, string) {
+	t.Helper()
+	request := httptest.NewRequest(method, "/log/level", strings.NewReader(body))
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	var state levelState
+	if recorder.Code == http.StatusOK {
+		if err := json.Unmarshal(recorder.Body.Bytes(), &state); err != nil {
+			t.Fatalf("解析回應失敗：%v", err)
+		}
+	}
+	return recorder.Code, state, recorder.Body.String()
+}
+
+func newLevelHandlerTestInstance(t *testing.T, level string) *Instance {
+	t.Helper()
+	cfg := fileOutputTestConfig(t.TempDir(), "app.log")
+	cfg.Level = level
+	instance, err := NewWithOptions(cfg)
+	if err != nil {
+		t.Fatalf("建立 Instance 失敗：%v", err)
+	}
+	t.Cleanup(func() { _ = instance.Close() })
+	return instance
+}
+
+func TestInstanceLevelHandlerGetAndPut(t *testing.T) {
+	instance := newLevelHandlerTestInstance(t, "info,db=debug")
+	handler := instance.LevelHandler()
+
+	status, state, _ := serveLevelRequest(t, handler, http.MethodGet, "")
+	if status != http.StatusOK || state.Spec != "info,db=debug" || state.Overrides["db"] != "debug" {
+		t.Fatalf("GET = %d %+v", status, state)
+	}
+
+	status, state, body := serveLevelRequest(t, handler, http.MethodPut, `{"level":"WARN"}`)
+	if status != http.StatusOK || state.Level != "warn" || state.Spec != "warn,db=debug" || state.ExpiresAt != nil {
+		t.Fatalf("PUT level = %d %+v %s", status, state, body)
+	}
+
+	status, state, body = serveLevelRequest(t, handler, http.MethodPost, `{"overrides":{"http":"error"}}`)
+	if status != http.StatusOK || state.Spec != "warn,http=error" {
+		t.Fatalf("POST overrides = %d %+v %s", status, state, body)
+	}
+	if spec := instance.LevelSpec(); spec != "warn,http=error" {
+		t.Fatalf("LevelSpec = %q，預期套用變更", spec)
+	}
+}
+
+func TestLevelHandlerRejectsInvalidRequests(t *testing.T) {
+	instance := newLevelHandlerTestInstance(t, "info")
+	handler := instance.LevelHandler()
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "未知級別", body: `{"level":"verbose"}`},
+		{name: "覆寫未知級別", body: `{"overrides":{"db":"trace"}}`},
+		{name: "空白名稱", body: `{"overrides":{"":"debug"}}`},
+		{name: "名稱含逗號", body: `{"overrides":{"a,b":"debug"}}`},
+		{name: "未知欄位", body: `{"level":"debug","ttl":"1m"}`},
+		{name: "缺少變更", body: `{"expires_in":"1m"}`},
+		{name: "expires_in 無效", body: `{"level":"debug","expires_in":"-1m"}`},
+		{name: "多個物件", body: `{"level":"debug"}{"level":"warn"}`},
+		{name: "非 JSON", body: `debug`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, _, body := serveLevelRequest(t, handler, http.MethodPut, tt.body)
+			if status != http.StatusBadRequest || !strings.Contains(body, `"error"`) {
+				t.Fatalf("回應 = %d %s，預期 400 與錯誤訊息", status, body)
+			}
+			if spec := instance.LevelSpec(); spec != "info" {
+				t.Fatalf("LevelSpec = %q，無效請求不應改變級別", spec)
+			}
+		})
+	}
+}
+
+func TestLevelHandlerRejectsUnsupportedMethod(t *testing.T) {
+	request := httptest.NewRequest(http.MethodDelete, "/log/level", nil)
+	recorder := httptest.NewRecorder()
+	newLevelHandlerTestInstance(t, "info").LevelHandler().ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusMethodNotAllowed || recorder.Header().Get("Allow") == "" {
+		t.Fatalf("DELETE = %d, Allow = %q", recorder.Code, recorder.Header().Get("Allow"))
+	}
+}
+
+func TestLevelHandlerExpiryRevertsChange(t *testing.T) {
+	instance := newLevelHandlerTestInstance(t, "info,db=warn")
+	handler := instance.LevelHandler()
+
+	status, state, body := serveLevelRequest(t, handler, http.MethodPut, `{"level":"debug","expires_in":"50ms"}`)
+	if status != http.StatusOK || state.ExpiresAt == nil || state.Spec != "debug,db=warn" {
+		t.Fatalf("PUT = %d %+v %s", status, state, body)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for instance.LevelSpec() != "info,db=warn" {
+		if time.Now().After(deadline) {
+			t.Fatalf("LevelSpec = %q，預期到期後恢復", instance.LevelSpec())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if _, state, _ := serveLevelRequest(t, handler, http.MethodGet, ""); state.ExpiresAt != nil {
+		t.Fatalf("恢復後 expires_at = %v，預期省略", state.ExpiresAt)
+	}
+}
+
+func TestLevelHandlerLaterChangeCancelsExpiry(t *testing.T) {
+	instance := newLevelHandlerTestInstance(t, "info")
+	handler := instance.LevelHandler()
+
+	serveLevelRequest(t, handler, http.MethodPut, `{"level":"debug","expires_in":"30ms"}`)
+	if err := instance.SetLevelSpec("error"); err != nil {
+		t.Fatalf("SetLevelSpec 失敗：%v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if spec := instance.LevelSpec(); spec != "error" {
+		t.Fatalf("LevelSpec = %q，後續變更應取消到期恢復", spec)
+	}
+}
+
+func TestGlobalLevelHandler(t *testing.T) {
+	resetGlobalState(t)
+	handler := LevelHandler()
+
+	status, _, body := serveLevelRequest(t, handler, http.MethodGet, "")
+	if status != http.StatusServiceUnavailable || !strings.Contains(body, ErrNotConfigured.Error()) {
+		t.Fatalf("未設定時 GET = %d %s", status, body)
+	}
+
+	cleanup, err := Configure(nil)
+	if err != nil {
+		t.Fatalf("Configure 失敗：%v", err)
+	}
+	defer func() { _ = cleanup() }()
+
+	status, state, body := serveLevelRequest(t, handler, http.MethodPut, `{"overrides":{"db":"debug"}}`)
+	if status != http.StatusOK || state.Spec != "info,db=debug" {
+		t.Fatalf("PUT = %d %+v %s", status, state, body)
+	}
+	if spec := LevelSpec(); spec != "info,db=debug" {
+		t.Fatalf("LevelSpec = %q，預期套用至全域 logger", spec)
+	}
+
+	serveLevelRequest(t, handler, http.MethodPut, `{"level":"debug","expires_in":"30ms"}`)
+	SetLevel("warn")
+	time.Sleep(100 * time.Millisecond)
+	if spec := LevelSpec(); spec != "warn,db=debug" {
+		t.Fatalf("LevelSpec = %q，SetLevel 應取消到期恢復", spec)
+	}
+}
@@ -0,0 +1,287 @@
+package zlogger
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// levelSpec 是解析後的級別規格：基礎級別與依 logger 名稱覆寫的級別。
+type levelSpec struct {
+	base      zapcore.Level
+	overrides map[string]zapcore.Level
+}
+
+// parseLevelSpec 解析 "info,db=debug,http.client=warn" 格式的級別規格。
+//
+// 必須恰好有一個不含 "=" 的基礎級別；名稱不可為空或重複，級別不分大小寫。
+func parseLevelSpec(spec string) (levelSpec, error) {
+	parsed := levelSpec{overrides: make(map[string]zapcore.Level)}
+	hasBase := false
+	for part := range strings.SplitSeq(spec, ",") {
+		part = strings.TrimSpace(part)
+		name, levelText, isOverride := strings.Cut(part, "=")
+		if !isOverride {
+			levelText = name
+		}
+		level, err := parseStrictLevel(strings.TrimSpace(levelText))
+		if err != nil {
+			return levelSpec{}, fmt.Errorf("%w: Level %q: %w", ErrInvalidConfig, spec, err)
+		}
+		if !isOverride {
+			if hasBase {
+				return levelSpec{}, fmt.Errorf("%w: Level %q 只能有一個基礎級別", ErrInvalidConfig, spec)
+			}
+			hasBase = true
+			parsed.base = level
+			continue
+		}
+
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return levelSpec{}, fmt.Errorf("%w: Level %q 的 logger 名稱不可為空", ErrInvalidConfig, spec)
+		}
+		if _, exists := parsed.overrides[name]; exists {
+			return levelSpec{}, fmt.Errorf("%w: Level %q 的 logger 名稱 %q 重複", ErrInvalidConfig, spec, name)
+		}
+		parsed.overrides[name] = level
+	}
+	if !hasBase {
+		return levelSpec{}, fmt.Errorf("%w: Level %q 缺少基礎級別", ErrInvalidConfig, spec)
+	}
+	return parsed, nil
+}
+
+// parseStrictLevel 解析 Config 支援的級別；與 parseLevel 不同，未知值回傳錯誤。
+func parseStrictLevel(level string) (zapcore.Level, error) {
+	switch strings.ToLower(level) {
+	case "debug", "info", "warn", "error", "fatal":
+		return parseLevel(level), nil
+	default:
+		return zapcore.InvalidLevel, fmt.Errorf("級別 %q 不受支援", level)
+	}
+}
+
+// normalizeLevelSpec 將級別轉為小寫並移除空白，保留 logger 名稱的大小寫。
+func normalizeLevelSpec(spec string) string {
+	parts := strings.Split(spec, ",")
+	for index, part := range parts {
+		name, level, isOverride := strings.Cut(part, "=")
+		if isOverride {
+			parts[index] = strings.TrimSpace(name) + "=" + strings.ToLower(strings.TrimSpace(level))
+		} else {
+			parts[index] = strings.ToLower(strings.TrimSpace(part))
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// String 以名稱排序輸出可再次解析的規格。
+func (s levelSpec) String() string {
+	names := make([]string, 0, len(s.overrides))
+	for name := range s.overrides {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var spec strings.Builder
+	spec.WriteString(s.base.String())
+	for _, name := range names {
+		spec.WriteString("," + name + "=" + s.overrides[name].String())
+	}
+	return spec.String()
+}
+
+// levelSnapshot 是一次發布的完整級別規格；minimum 為覆寫中的最低級別。
+type levelSnapshot struct {
+	spec    levelSpec
+	minimum zapcore.Level
+}
+
+// nameLevels 依 logger 名稱決定級別：名稱或其上層名稱有覆寫時使用覆寫，否則使用基礎級別。
+//
+// 基礎級別與覆寫以同一個 snapshot 發布，並行的 Check 不會看到新舊混合的規格。base 是
+// 基礎級別的 AtomicLevel 鏡像，供 zapGlobalLevel 讀取。整批替換以 mu 序列化，並管理
+// 到期後恢復先前規格的 timer。
+type nameLevels struct {
+	base    zap.AtomicLevel
+	current atomic.Pointer[levelSnapshot]
+
+	mu         sync.Mutex
+	revert     *time.Timer
+	expiresAt  time.Time
+	generation uint64
+}
+
+func newNameLevels(spec levelSpec) *nameLevels {
+	levels := &nameLevels{base: zap.NewAtomicLevelAt(spec.base)}
+	levels.store(spec)
+	return levels
+}
+
+// set 以 spec 替換基礎級別與所有覆寫，並取消尚未到期的恢復。
+func (n *nameLevels) set(spec levelSpec) {
+	n.update(func(levelSpec) levelSpec { return spec }, 0)
+}
+
+// update 在 mu 內以 merge 由目前規格計算新規格並套用，並行的部分變更不會互相覆蓋。
+// expiry 大於 0 時，到期後恢復套用前的規格。回傳套用的規格與到期時間。
+//
+// 到期前再次變更會取消這次恢復。
+func (n *nameLevels) update(merge func(levelSpec) levelSpec, expiry time.Duration) (levelSpec, time.Time) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	previous := n.spec()
+	spec := merge(previous)
+	n.cancelRevertLocked()
+	n.store(spec)
+	if expiry <= 0 {
+		return spec, time.Time{}
+	}
+
+	generation := n.generation
+	n.expiresAt = time.Now().Add(expiry)
+	n.revert = time.AfterFunc(expiry, func() {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		if n.generation != generation {
+			return
+		}
+		n.cancelRevertLocked()
+		n.store(previous)
+	})
+	return spec, n.expiresAt
+}
+
+// setBase 只替換基礎級別並保留覆寫；與其他變更相同，會取消尚未到期的恢復。
+func (n *nameLevels) setBase(level zapcore.Level) {
+	n.update(func(spec levelSpec) levelSpec {
+		spec.base = level
+		return spec
+	}, 0)
+}
+
+// cancelRevertLocked 取消尚未到期的恢復；呼叫端必須持有 mu。
+func (n *nameLevels) cancelRevertLocked() {
+	if n.revert != nil {
+		n.revert.Stop()
+		n.revert = nil
+	}
+	n.expiresAt = time.Time{}
+	n.generation++
+}
+
+// stopRevert 取消尚未到期的恢復，保留目前規格。
+func (n *nameLevels) stopRevert() {
+	n.mu.Lock()
+	n.cancelRevertLocked()
+	n.mu.Unlock()
+}
+
+// state 回傳目前規格與尚未到期的恢復時間；沒有待恢復的變更時時間為零值。
+func (n *nameLevels) state() (levelSpec, time.Time) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.spec(), n.expiresAt
+}
+
+// store 發布 spec；呼叫端不得再修改 spec.overrides。
+func (n *nameLevels) store(spec levelSpec) {
+	minimum := zapcore.InvalidLevel
+	for _, level := range spec.overrides {
+		if minimum == zapcore.InvalidLevel || level < minimum {
+			minimum = level
+		}
+	}
+	n.current.Store(&levelSnapshot{spec: spec, minimum: minimum})
+	n.base.SetLevel(spec.base)
+}
+
+// spec 回傳目前的級別規格。
+func (n *nameLevels) spec() levelSpec {
+	return n.current.Load().spec
+}
+
+// Enabled 回報 level 是否可能被任一名稱啟用，供內層 core 與 zap 的快速檢查使用。
+func (n *nameLevels) Enabled(level zapcore.Level) bool {
+	snapshot := n.current.Load()
+	if snapshot.spec.base.Enabled(level) {
+		return true
+	}
+	return snapshot.minimum != zapcore.InvalidLevel && level >= snapshot.minimum
+}
+
+// Level 回傳基礎級別與覆寫中的最低級別，供 zapcore.LevelOf 使用。
+func (n *nameLevels) Level() zapcore.Level {
+	snapshot := n.current.Load()
+	if snapshot.minimum != zapcore.InvalidLevel && snapshot.minimum < snapshot.spec.base {
+		return snapshot.minimum
+	}
+	return snapshot.spec.base
+}
+
+// allows 依 entry 的 logger 名稱由下往上尋找覆寫，例如 http.client.pool、http.client、http。
+func (n *nameLevels) allows(entry zapcore.Entry) bool {
+	spec := n.current.Load().spec
+	if len(spec.overrides) > 0 {
+		name := entry.LoggerName
+		for name != "" {
+			if level, ok := spec.overrides[name]; ok {
+				return entry.Level >= level
+			}
+			index := strings.LastIndexByte(name, '.')
+			if index < 0 {
+				break
+			}
+			name = name[:index]
+		}
+	}
+	return spec.base.Enabled(entry.Level)
+}
+
+// namedLevelCore 在 Check 階段依 logger 名稱套用 nameLevels。
+type namedLevelCore struct {
+	zapcore.Core
+	levels *nameLevels
+}
+
+func (c *namedLevelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &namedLevelCore{Core: c.Core.With(fields), levels: c.levels}
+}
+
+func (c *namedLevelCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.levels.allows(entry) {
+		return checked
+	}
+	return c.Core.Check(entry, checked)
+}
+
+// SetLevelSpec 以 "info,db=debug" 格式整批替換 Instance 的基礎級別與名稱覆寫。
+//
+// 規格無效時回傳包裝 ErrInvalidConfig 的錯誤並保留目前設定；nil Instance 回傳 ErrNotConfigured。
+func (i *Instance) SetLevelSpec(spec string) error {
+	if i == nil {
+		return ErrNotConfigured
+	}
+	parsed, err := parseLevelSpec(spec)
+	if err != nil {
+		return err
+	}
+	i.levels.set(parsed)
+	return nil
+}
+
+// LevelSpec 回傳目前生效的級別規格，覆寫依名稱排序；nil Instance 回傳空字串。
+func (i *Instance) LevelSpec() string {
+	if i == nil {
+		return ""
+	}
+	return i.levels.spec().String()
+}
@@ -0,0 +1,233 @@
+package zlogger
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestParseLevelSpec(t *testing.T) {
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{spec: "info", want: "info"},
+		{spec: "INFO", want: "info"},
+		{spec: "info,db=debug,http.client=warn", want: "info,db=debug,http.client=warn"},
+		{spec: " http.client = WARN , error , db=debug ", want: "error,db=debug,http.client=warn"},
+	}
+	for _, tt := range tests {
+		spec, err := parseLevelSpec(tt.spec)
+		if err != nil {
+			t.Fatalf("解析 %q 失敗：%v", tt.spec, err)
+		}
+		if got := spec.String(); got != tt.want {
+			t.Fatalf("解析 %q = %q，預期 %q", tt.spec, got, tt.want)
+		}
+	}
+
+	for _, invalid := range []string{"", "trace", "db=debug", "info,warn", "info,=debug", "info,db=trace", "info,db=debug,db=warn"} {
+		if _, err := parseLevelSpec(invalid); !errors.Is(err, ErrInvalidConfig) {
+			t.Fatalf("解析 %q 錯誤 = %v，預期 ErrInvalidConfig", invalid, err)
+		}
+	}
+}
+
+func TestConfigNormalizesLevelSpecKeepingNames(t *testing.T) {
+	patchLevel := " INFO , DB.Pool=Debug "
+	cfg, err := (&ConfigPatch{Level: &patchLevel}).Resolve()
+	if err != nil {
+		t.Fatalf("Resolve 失敗：%v", err)
+	}
+	if cfg.Level != "info,DB.Pool=debug" {
+		t.Fatalf("Level = %q，預期保留 logger 名稱大小寫", cfg.Level)
+	}
+}
+
+func TestInstanceAppliesLevelOverridesByName(t *testing.T) {
+	base := t.TempDir()
+	cfg := fileOutputTestConfig(base, "app.log")
+	cfg.Level = "info,db=debug,http.client=warn"
+	instance, err := NewWithOptions(cfg)
+	if err != nil {
+		t.Fatalf("建立 Instance 失敗：%v", err)
+	}
+	logger := instance.Logger()
+	logger.Debug("root debug")
+	logger.Named("db").Debug("db debug")
+	logger.Named("db").Named("pool").Debug("db.pool debug")
+	logger.Named("dbx").Debug("dbx debug")
+	logger.Named("http").Info("http info")
+	logger.Named("http").Named("client").Info("http.client info")
+	logger.Named("http").Named("client").Warn("http.client warn")
+
+	if got := instance.LevelSpec(); got != "info,db=debug,http.client=warn" {
+		t.Fatalf("LevelSpec = %q", got)
+	}
+	if err := instance.SetLevelSpec("info,db=trace"); !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("無效規格錯誤 = %v，預期 ErrInvalidConfig", err)
+	}
+	if err := instance.SetLevelSpec("warn"); err != nil {
+		t.Fatalf("SetLevelSpec 失敗：%v", err)
+	}
+	logger.Named("db").Info("db info after")
+	if err := instance.Close(); err != nil {
+		t.Fatalf("關閉 Instance 失敗：%v", err)
+	}
+
+	//nolint:gosec // 測試只讀取 t.TempDir 內的預期檔案。
+	content, err := os.ReadFile(filepath.Join(base, "app.log"))
+	if err != nil {
+		t.Fatalf("讀取日誌失敗：%v", err)
+	}
+	for _, message := range []string{"db debug", "db.pool debug", "http info", "http.client warn"} {
+		if !strings.Contains(string(content), message) {
+			t.Errorf("日誌缺少 %q", message)
+		}
+	}
+	for _, message := range []string{"root debug", "dbx debug", "http.client info", "db info after"} {
+		if strings.Contains(string(content), message) {
+			t.Errorf("日誌不應包含 %q", message)
+		}
+	}
+}
+
+func TestGlobalSetLevelSpec(t *testing.T) {
+	resetGlobalState(t)
+	t.Cleanup(func() { resetGlobalState(t) })
+
+	if err := SetLevelSpec("debug"); !errors.Is(err, ErrNotConfigured) {
+		t.Fatalf("未設定時錯誤 = %v，預期 ErrNotConfigured", err)
+	}
+	if got := LevelSpec(); got != "" {
+		t.Fatalf("未設定時 LevelSpec = %q", got)
+	}
+
+	cleanup, err := Configure(nil)
+	if err != nil {
+		t.Fatalf("Configure 失敗：%v", err)
+	}
+	defer func() { _ = cleanup() }()
+
+	if err := SetLevelSpec("info,db=debug"); err != nil {
+		t.Fatalf("SetLevelSpec 失敗：%v", err)
+	}
+	SetLevel("error")
+	if got := LevelSpec(); got != "error,db=debug" {
+		t.Fatalf("LevelSpec = %q，預期 SetLevel 只調整基礎級別", got)
+	}
+	if !GetLogger().Core().Enabled(DebugLevel) {
+		t.Fatal("db=debug 覆寫應讓 core 接受 DEBUG")
+	}
+}
+
+func TestNilInstanceLevelMethods(t *testing.T) {
+	var instance *Instance
+	if err := instance.SetLevelSpec("debug"); !errors.Is(err, ErrNotConfigured) {
+		t.Fatalf("SetLevelSpec 錯誤 = %v，預期 ErrNotConfigured", err)
+	}
+	if got := instance.LevelSpec(); got != "" {
+		t.Fatalf("LevelSpec = %q，預期空字串", got)
+	}
+	status, _, body := serveLevelRequest(t, instance.LevelHandler(), http.MethodGet, "")
+	if status != http.StatusServiceUnavailable || !strings.Contains(body, ErrNotConfigured.Error()) {
+		t.Fatalf("GET = %d %s，預期 503", status, body)
+	}
+}
+
+func TestNameLevelsPublishesSpecAtomically(t *testing.T) {
+	verbose, err := parseLevelSpec("debug,db=info")
+	if err != nil {
+		t.Fatalf("parseLevelSpec 失敗：%v", err)
+	}
+	quiet, err := parseLevelSpec("info")
+	if err != nil {
+		t.Fatalf("parseLevelSpec 失敗：%v", err)
+	}
+	levels := newNameLevels(verbose)
+
+	stop := make(chan struct{})
+	time.AfterFunc(200*time.Millisecond, func() { close(stop) })
+	var mixed atomic.Bool
+	var wg sync.WaitGroup
+	// 兩個規格都不允許 db 的 DEBUG；新舊混合時才會出現 debug 基礎級別且沒有 db 覆寫。
+	entry := zapcore.Entry{LoggerName: "db", Level: zapcore.DebugLevel}
+	for range 4 {
+		wg.Go(func() {
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					if levels.allows(entry) {
+						mixed.Store(true)
+					}
+				}
+			}
+		})
+	}
+	for index := 0; ; index++ {
+		select {
+		case <-stop:
+			wg.Wait()
+			if mixed.Load() {
+				t.Fatal("切換規格期間看到新舊混合的級別")
+			}
+			return
+		default:
+		}
+		if index%2 == 0 {
+			levels.set(quiet)
+		} else {
+			levels.set(verbose)
+		}
+	}
+}
+
+func TestNameLevelsUpdateMergesConcurrentChanges(t *testing.T) {
+	spec, err := parseLevelSpec("info")
+	if err != nil {
+		t.Fatalf("parseLevelSpec 失敗：%v", err)
+	}
+	levels := newNameLevels(spec)
+
+	entered, release := make(chan struct{}), make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		levels.update(func(spec levelSpec) levelSpec {
+			close(entered)
+			<-release
+			spec.base = zapcore.WarnLevel
+			return spec
+		}, 0)
+	})
+	<-entered
+
+	overridden := make(chan struct{})
+	wg.Go(func() {
+		defer close(overridden)
+		levels.update(func(spec levelSpec) levelSpec {
+			spec.overrides = map[string]zapcore.Level{"db": zapcore.DebugLevel}
+			return spec
+		}, 0)
+	})
+	select {
+	case <-overridden:
+		t.Fatal("另一個變更仍在合併時不應套用")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(release)
+	wg.Wait()
+
+	if got := levels.spec().String(); got != "warn,db=debug" {
+		t.Fatalf("spec = %q，並行的部分變更不應互相覆蓋", got)
+	}
+}
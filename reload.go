@@ -0,0 +1,262 @@
+package zlogger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ErrReloadRejected 表示 Reload 未套用新設定，Instance 維持原本的設定與輸出。
+var ErrReloadRejected = errors.New("設定變更未套用")
+
+// reloadRestartKeys 是建立 zap logger 時即固定的欄位，變更需要重新建立 Instance。
+var reloadRestartKeys = []string{"add_caller", "add_stacktrace", "development"}
+
+// Reload 比對 cfg 與目前套用的設定，並將差異套用至執行中的 Instance。nil Config 會使用
+// DefaultConfig。
+//
+// Level 變更直接替換級別規格；其餘欄位（format、outputs、路徑、檔名與 sampling）變更時，
+// 會依新設定建立完整輸出後一次替換，既有 Logger 與其 With 衍生的 logger 立即改用新輸出，
+// 舊輸出在進行中的寫入完成後關閉。output 種類、目錄與檔名（split 為 prefix）未變更的檔案
+// 輸出直接沿用，換檔排程、保留與壓縮不受影響。Options 於 New 時決定，Reload 不會變更。
+//
+// 設定無效、變更 add_caller、add_stacktrace 或 development，或無法建立新輸出時，回傳包裝
+// ErrReloadRejected 的錯誤，目前的 logger 不受影響。關閉舊輸出的錯誤與等待進行中寫入逾時
+// 以 InternalEventConfigReload 交給 ErrorHandler。Instance 是 Configure 設定的全域 logger
+// 時，一併更新全域設定。
+func (i *Instance) Reload(cfg *Config) error {
+	if i == nil {
+		return nil
+	}
+	if cfg == nil {
+		cfg = DefaultConfig()
+	} else {
+		cfg = cfg.normalizedCopy()
+	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrReloadRejected, err)
+	}
+
+	i.reloadMu.Lock()
+	defer i.reloadMu.Unlock()
+
+	changed := diffConfig(i.config, cfg)
+	if len(changed) == 0 {
+		return nil
+	}
+	for _, key := range changed {
+		if slices.Contains(reloadRestartKeys, key) {
+			return fmt.Errorf("%w: %s 需要重新建立 Instance", ErrReloadRejected, key)
+		}
+	}
+
+	levelChanged := slices.Contains(changed, "level")
+	var spec levelSpec
+	if levelChanged {
+		parsed, err := parseLevelSpec(cfg.Level)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrReloadRejected, err)
+		}
+		spec = parsed
+	}
+	if i.isClosed() {
+		return fmt.Errorf("重新載入 logger instance: %w", os.ErrClosed)
+	}
+	var generation *coreGeneration
+	if !levelChanged || len(changed) > 1 {
+		// 只有 Reload 替換 generation，持有 reloadMu 期間目前的 generation 不變。
+		built, err := newCoreGeneration(cfg, i.settings, i.levels, i.sampled, i.dropped, i.core.current.Load())
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrReloadRejected, err)
+		}
+		generation = built
+	}
+
+	i.mu.Lock()
+	if i.closed {
+		i.mu.Unlock()
+		closedErr := fmt.Errorf("重新載入 logger instance: %w", os.ErrClosed)
+		if generation == nil {
+			return closedErr
+		}
+		// 沿用的輸出仍屬於舊 generation，由 Close 關閉。
+		generation.disown(generation.inherited)
+		return errors.Join(closedErr, generation.retire("回收 logger 資源"))
+	}
+	var previous *coreGeneration
+	if generation != nil {
+		previous = i.core.swap(generation)
+		previous.disown(generation.inherited)
+		i.closers = generation.closers
+	}
+	if levelChanged {
+		i.levels.set(spec)
+	}
+	i.config = cfg
+	i.mu.Unlock()
+
+	configureMu.Lock()
+	if globalInstance == i {
+		globalConfig = cfg.normalizedCopy()
+	}
+	configureMu.Unlock()
+
+	if previous != nil {
+		i.settings.reportInternalEvent(InternalEventConfigReload, "", previous.retire("關閉舊的 logger 資源"))
+	}
+	i.logger.Info("logger config reloaded", zap.Strings("changed", changed))
+	return nil
+}
+
+func (i *Instance) isClosed() bool {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.closed
+}
+
+// Config 回傳最近一次由 New 或 Reload 套用之設定的副本。
+//
+// SetLevelSpec 與 LevelHandler 的執行期調整不反映在 Level，目前級別請使用 LevelSpec。
+func (i *Instance) Config() *Config {
+	if i == nil {
+		return nil
+	}
+	i.reloadMu.Lock()
+	defer i.reloadMu.Unlock()
+	return i.config.normalizedCopy()
+}
+
+// diffConfig 回傳 previous 與 next 不同之欄位的 json key，依 Config 欄位順序排列。
+func diffConfig(previous, next *Config) []string {
+	previousValue := reflect.ValueOf(previous).Elem()
+	nextValue := reflect.ValueOf(next).Elem()
+	configType := previousValue.Type()
+
+	var changed []string
+	for index := range configType.NumField() {
+		if !reflect.DeepEqual(previousValue.Field(index).Interface(), nextValue.Field(index).Interface()) {
+			changed = append(changed, jsonFieldKey(configType.Field(index)))
+		}
+	}
+	return changed
+}
+
+// ConfigWatcher 定期檢查設定檔，內容變更時以 Instance.Reload 套用。
+type ConfigWatcher struct {
+	instance *Instance
+	path     string
+	layers   []ConfigLayer
+
+	content []byte
+	lastErr string
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// WatchConfigFile 讀取 path 指向的 JSON 或 TOML 設定檔並立即套用，之後每隔 interval
+// 檢查一次，內容變更時重新套用。
+//
+// 設定檔以名為 "file" 的 ConfigLayer 解析，overrides 依序疊加在其上，例如環境變數與
+// 命令列 flag，使其維持優先。首次讀取或套用失敗時直接回傳錯誤且不啟動監看；之後的
+// 讀取、解析或 Reload 錯誤以 InternalEventConfigReload 交給 ErrorHandler，相同錯誤
+// 只回報一次，目前設定維持不變直到檔案再次變更。Instance 關閉後，監看於下一次檢查時
+// 停止；Instance 為 nil 或已關閉時回傳錯誤。
+//
+// 監看以輪詢比對檔案內容，不依賴檔案系統通知。呼叫端應以 Close 停止監看。
+func (i *Instance) WatchConfigFile(path string, interval time.Duration, overrides ...ConfigLayer) (*ConfigWatcher, error) {
+	if i == nil {
+		return nil, fmt.Errorf("監看設定檔: Instance 為 nil: %w", os.ErrInvalid)
+	}
+	if i.isClosed() {
+		return nil, fmt.Errorf("監看設定檔: %w", os.ErrClosed)
+	}
+	if interval <= 0 {
+		return nil, fmt.Errorf("%w: 設定檔監看間隔必須大於 0", ErrInvalidConfig)
+	}
+	if err := checkConfigFileExt(path); err != nil {
+		return nil, err
+	}
+
+	watcher := &ConfigWatcher{
+		instance: i,
+		path:     path,
+		layers:   slices.Clone(overrides),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	data, err := readConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := watcher.apply(data); err != nil {
+		return nil, err
+	}
+
+	go func() {
+		defer close(watcher.done)
+		runEvery(systemRotationClock{}, interval, watcher.stop, watcher.poll)
+	}()
+	return watcher, nil
+}
+
+// Close 停止監看並等待背景 goroutine 結束，可安全重複呼叫。
+func (w *ConfigWatcher) Close() error {
+	if w == nil {
+		return nil
+	}
+	w.stopOnce.Do(func() { close(w.stop) })
+	<-w.done
+	return nil
+}
+
+// poll 在內容變更時重新套用設定檔，並回報與上次不同的錯誤；Instance 已關閉時停止監看。
+func (w *ConfigWatcher) poll() {
+	if w.instance.isClosed() {
+		w.stopOnce.Do(func() { close(w.stop) })
+		return
+	}
+	data, err := readConfigFile(w.path)
+	if err == nil {
+		if bytes.Equal(data, w.content) {
+			w.lastErr = ""
+			return
+		}
+		err = w.apply(data)
+	}
+	if errors.Is(err, os.ErrClosed) {
+		w.stopOnce.Do(func() { close(w.stop) })
+		return
+	}
+	if err == nil {
+		w.lastErr = ""
+		return
+	}
+	if message := err.Error(); message != w.lastErr {
+		w.lastErr = message
+		w.instance.settings.reportInternalEvent(InternalEventConfigReload, w.path, err)
+	}
+}
+
+// apply 解析 data 並套用；無論結果為何都記錄 data，避免同一內容重複套用。
+func (w *ConfigWatcher) apply(data []byte) error {
+	w.content = data
+	patch, err := parseConfigPatch(w.path, data)
+	if err != nil {
+		return err
+	}
+	layers := append([]ConfigLayer{{Name: "file", Patch: patch}}, w.layers...)
+	resolved, err := ResolveConfigLayers(layers...)
+	if err != nil {
+		return err
+	}
+	return w.instance.Reload(resolved.Config)
+}
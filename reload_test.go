@@ -0,0 +1,416 @@
+package zlogger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func readLogFile(t *testing.T, path string) string {
+	t.Helper()
+	//nolint:gosec // 測試只讀取 t.TempDir 內的預期檔案。
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("讀取日誌檔失敗：%v", err)
+	}
+	return string(data)
+}
+
+func waitForReload(t *testing.T, done func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !done() {
+		if time.Now().After(deadline) {
+			t.Fatal("等待設定檔監看逾時")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// replaceConfigFile 以暫存檔改名更新設定檔，避免監看讀到寫到一半的內容。
+func replaceConfigFile(t *testing.T, path, content string) {
+	t.Helper()
+	temp := path + ".tmp"
+	if err := os.WriteFile(temp, []byte(content), 0o600); err != nil {
+		t.Fatalf("寫入暫存設定檔失敗：%v", err)
+	}
+	if err := os.Rename(temp, path); err != nil {
+		t.Fatalf("更新設定檔失敗：%v", err)
+	}
+}
+
+func TestInstanceReloadSwitchesOutputsForExistingLoggers(t *testing.T) {
+	base := t.TempDir()
+	instance, err := New(fileOutputTestConfig(base, "before.log"))
+	if err != nil {
+		t.Fatalf("New 失敗：%v", err)
+	}
+	defer func() { _ = instance.Close() }()
+
+	child := instance.Logger().Named("db").With(String("request_id", "r1"))
+	child.Info("before reload")
+
+	cfg := fileOutputTestConfig(base, "after.log")
+	cfg.Level = "warn,db=debug"
+	if err := instance.Reload(cfg); err != nil {
+		t.Fatalf("Reload 失敗：%v", err)
+	}
+	child.Debug("after reload")
+	instance.Logger().Info("filtered by new level")
+
+	before := readLogFile(t, filepath.Join(base, "before.log"))
+	after := readLogFile(t, filepath.Join(base, "after.log"))
+	if !strings.Contains(before, "before reload") || strings.Contains(before, "after reload") {
+		t.Fatalf("舊檔案內容 = %q", before)
+	}
+	if !strings.Contains(after, `"msg":"after reload"`) || !strings.Contains(after, `"request_id":"r1"`) {
+		t.Fatalf("新檔案內容 = %q，預期包含 With 欄位", after)
+	}
+	if strings.Contains(after, "filtered by new level") {
+		t.Fatalf("新檔案內容 = %q，INFO 應被新級別過濾", after)
+	}
+	if got := instance.LevelSpec(); got != "warn,db=debug" {
+		t.Fatalf("LevelSpec = %q", got)
+	}
+	if got := instance.Config(); got.FileName != "after.log" || got.Level != "warn,db=debug" {
+		t.Fatalf("Config = %+v", got)
+	}
+}
+
+func TestInstanceReloadRejectsChangesWithoutSideEffects(t *testing.T) {
+	base := t.TempDir()
+	blocker := filepath.Join(base, "blocker")
+	if err := os.WriteFile(blocker, nil, 0o600); err != nil {
+		t.Fatalf("建立檔案失敗：%v", err)
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(*Config)
+	}{
+		{name: "add_caller", mutate: func(cfg *Config) { cfg.AddCaller = true }},
+		{name: "development", mutate: func(cfg *Config) { cfg.Development = true; cfg.Level = "debug" }},
+		{name: "驗證失敗", mutate: func(cfg *Config) { cfg.Outputs = []string{"syslog"} }},
+		{name: "無法建立輸出", mutate: func(cfg *Config) { cfg.LogPath = filepath.Join(blocker, "logs"); cfg.Level = "debug" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instance, err := New(fileOutputTestConfig(base, tt.name+".log"))
+			if err != nil {
+				t.Fatalf("New 失敗：%v", err)
+			}
+			defer func() { _ = instance.Close() }()
+
+			cfg := fileOutputTestConfig(base, tt.name+".log")
+			tt.mutate(cfg)
+			if err := instance.Reload(cfg); !errors.Is(err, ErrReloadRejected) {
+				t.Fatalf("Reload 錯誤 = %v，預期 ErrReloadRejected", err)
+			}
+			if instance.LevelSpec() != "info" || instance.Config().LogPath != base {
+				t.Fatalf("被拒絕的變更不應生效：%q %+v", instance.LevelSpec(), instance.Config())
+			}
+			instance.Logger().Info("still writing")
+			if content := readLogFile(t, filepath.Join(base, tt.name+".log")); !strings.Contains(content, "still writing") {
+				t.Fatalf("日誌檔內容 = %q", content)
+			}
+		})
+	}
+}
+
+func TestInstanceReloadKeepsStatsAndRejectsAfterClose(t *testing.T) {
+	base := t.TempDir()
+	cfg := fileOutputTestConfig(base, "app.log")
+	cfg.Sampling = &SamplingConfig{Initial: 1, Thereafter: 0, Tick: "1h"}
+	instance, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New 失敗：%v", err)
+	}
+	for range 3 {
+		instance.Logger().Info("sampled")
+	}
+	before := instance.Stats().SampledEntries
+
+	cfg.Sampling = nil
+	if err := instance.Reload(cfg); err != nil {
+		t.Fatalf("Reload 失敗：%v", err)
+	}
+	if got := instance.Stats().SampledEntries; got != before || got == 0 {
+		t.Fatalf("SampledEntries = %d，預期保留 %d", got, before)
+	}
+
+	if err := instance.Close(); err != nil {
+		t.Fatalf("Close 失敗：%v", err)
+	}
+	cfg.FileName = "closed.log"
+	if err := instance.Reload(cfg); !errors.Is(err, os.ErrClosed) {
+		t.Fatalf("Close 後 Reload 錯誤 = %v，預期 os.ErrClosed", err)
+	}
+	assertPathDoesNotExist(t, filepath.Join(base, "closed.log"))
+}
+
+func TestInstanceReloadWhileLogging(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []FileOutputOption
+	}{
+		{name: "同步"},
+		{name: "非同步", opts: []FileOutputOption{WithAsync(64, OverflowBlock())}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := t.TempDir()
+			handler := &recordingErrorHandler{}
+			opts := append(slices.Clone(tt.opts), WithErrorHandler(handler.handle))
+			instance, err := NewWithOptions(fileOutputTestConfig(base, "0.log"), opts...)
+			if err != nil {
+				t.Fatalf("NewWithOptions 失敗：%v", err)
+			}
+			defer func() { _ = instance.Close() }()
+
+			var wg sync.WaitGroup
+			stop := make(chan struct{})
+			for range 4 {
+				wg.Go(func() {
+					logger := instance.Logger().With(String("worker", "w"))
+					for {
+						select {
+						case <-stop:
+							return
+						default:
+							logger.Info("concurrent")
+						}
+					}
+				})
+			}
+			for index := range 5 {
+				if err := instance.Reload(fileOutputTestConfig(base, string(rune('1'+index))+".log")); err != nil {
+					t.Errorf("Reload 失敗：%v", err)
+				}
+			}
+			close(stop)
+			wg.Wait()
+
+			if err := instance.Sync(); err != nil {
+				t.Fatalf("Sync 失敗：%v", err)
+			}
+			if content := readLogFile(t, filepath.Join(base, "5.log")); !strings.Contains(content, "logger config reloaded") {
+				t.Fatalf("最後的日誌檔內容 = %q", content)
+			}
+			if events := handler.snapshot(); len(events) != 0 {
+				t.Fatalf("events = %+v，替換期間不應寫入已關閉的檔案", events)
+			}
+		})
+	}
+}
+
+func TestInstanceReloadWaitsForHeldEntries(t *testing.T) {
+	base := t.TempDir()
+	handler := &recordingErrorHandler{}
+	instance, err := NewWithOptions(fileOutputTestConfig(base, "old.log"), WithErrorHandler(handler.handle))
+	if err != nil {
+		t.Fatalf("NewWithOptions 失敗：%v", err)
+	}
+	defer func() { _ = instance.Close() }()
+
+	held := instance.Logger().Check(InfoLevel, "held entry")
+	done := make(chan error, 1)
+	started := time.Now()
+	go func() { done <- instance.Reload(fileOutputTestConfig(base, "new.log")) }()
+	select {
+	case err := <-done:
+		t.Fatalf("Reload 在寫入完成前返回：%v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+	held.Write()
+	if err := <-done; err != nil {
+		t.Fatalf("Reload 失敗：%v", err)
+	}
+	if elapsed := time.Since(started); elapsed >= generationDrainTimeout {
+		t.Fatalf("Reload 耗時 %s，寫入完成後應立即關閉舊輸出", elapsed)
+	}
+	if content := readLogFile(t, filepath.Join(base, "old.log")); !strings.Contains(content, "held entry") {
+		t.Fatalf("舊檔案內容 = %q", content)
+	}
+	if events := handler.snapshot(); len(events) != 0 {
+		t.Fatalf("events = %+v，預期沒有逾時", events)
+	}
+
+	instance.Logger().Check(InfoLevel, "never written")
+	if err := instance.Reload(fileOutputTestConfig(base, "last.log")); err != nil {
+		t.Fatalf("Reload 失敗：%v", err)
+	}
+	events := handler.snapshot()
+	if len(events) != 1 || events[0].Kind != InternalEventConfigReload || !errors.Is(events[0].Err, errGenerationDrainTimeout) {
+		t.Fatalf("events = %+v，預期回報等待逾時", events)
+	}
+}
+
+func TestReloadUpdatesGlobalConfig(t *testing.T) {
+	resetGlobalState(t)
+	cleanup, err := Configure(nil)
+	if err != nil {
+		t.Fatalf("Configure 失敗：%v", err)
+	}
+	defer func() { _ = cleanup() }()
+
+	configureMu.Lock()
+	instance := globalInstance
+	configureMu.Unlock()
+	cfg := instance.Config()
+	cfg.Level = "warn,db=debug"
+	if err := instance.Reload(cfg); err != nil {
+		t.Fatalf("Reload 失敗：%v", err)
+	}
+
+	configureMu.Lock()
+	level := globalConfig.Level
+	configureMu.Unlock()
+	if level != "warn,db=debug" {
+		t.Fatalf("globalConfig.Level = %q，預期反映 Reload", level)
+	}
+}
+
+func TestWatchConfigFileAppliesEditsAndReportsErrors(t *testing.T) {
+	base := t.TempDir()
+	handler := &recordingErrorHandler{}
+	instance, err := NewWithOptions(fileOutputTestConfig(base, "app.log"), WithErrorHandler(handler.handle))
+	if err != nil {
+		t.Fatalf("NewWithOptions 失敗：%v", err)
+	}
+	defer func() { _ = instance.Close() }()
+
+	content := fmt.Sprintf(`{"level": "info", "format": "json", "outputs": ["file"], "log_path": %q,
+		"file_name": "app.log", "split_prefix": "", "add_caller": false, "color_enabled": false}`, base)
+	path := writeConfigFile(t, "logger.json", content)
+	level := "warn"
+	watcher, err := instance.WatchConfigFile(path, 5*time.Millisecond, ConfigLayer{Name: "env", Patch: &ConfigPatch{Level: &level}})
+	if err != nil {
+		t.Fatalf("WatchConfigFile 失敗：%v", err)
+	}
+	defer func() { _ = watcher.Close() }()
+	if instance.LevelSpec() != "warn" {
+		t.Fatalf("LevelSpec = %q，預期 env layer 覆寫", instance.LevelSpec())
+	}
+
+	edited := strings.Replace(content, `"app.log"`, `"edited.log"`, 1)
+	replaceConfigFile(t, path, edited)
+	waitForReload(t, func() bool { return instance.Config().FileName == "edited.log" })
+
+	replaceConfigFile(t, path, `{"outputs": ["syslog"]}`)
+	waitForReload(t, func() bool { return len(handler.snapshot()) > 0 })
+	time.Sleep(20 * time.Millisecond)
+
+	events := handler.snapshot()
+	if len(events) != 1 || events[0].Kind != InternalEventConfigReload || events[0].Path != path ||
+		!errors.Is(events[0].Err, ErrInvalidConfig) {
+		t.Fatalf("events = %+v，預期一次 config reload 事件", events)
+	}
+	if got := instance.Config(); got.FileName != "edited.log" || !slices.Equal(got.Outputs, []string{"file"}) {
+		t.Fatalf("Config = %+v，無效的變更不應生效", got)
+	}
+}
+
+func TestWatchConfigFileStopsAfterInstanceClose(t *testing.T) {
+	instance, err := New(nil)
+	if err != nil {
+		t.Fatalf("New 失敗：%v", err)
+	}
+	path := writeConfigFile(t, "logger.json", `{"level": "warn"}`)
+	watcher, err := instance.WatchConfigFile(path, 5*time.Millisecond)
+	if err != nil {
+		t.Fatalf("WatchConfigFile 失敗：%v", err)
+	}
+	defer func() { _ = watcher.Close() }()
+
+	if err := instance.Close(); err != nil {
+		t.Fatalf("Close 失敗：%v", err)
+	}
+	select {
+	case <-watcher.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Instance 關閉且設定檔未變更時，監看應停止")
+	}
+	if _, err := instance.WatchConfigFile(path, time.Second); !errors.Is(err, os.ErrClosed) {
+		t.Fatalf("已關閉 Instance 錯誤 = %v，預期 os.ErrClosed", err)
+	}
+}
+
+func TestWatchConfigFileRejectsInvalidArguments(t *testing.T) {
+	instance, err := New(nil)
+	if err != nil {
+		t.Fatalf("New 失敗：%v", err)
+	}
+	defer func() { _ = instance.Close() }()
+
+	path := writeConfigFile(t, "logger.json", `{"add_caller": false}`)
+	var nilInstance *Instance
+	if _, err := nilInstance.WatchConfigFile(path, time.Second); !errors.Is(err, os.ErrInvalid) {
+		t.Fatalf("nil Instance 錯誤 = %v，預期 os.ErrInvalid", err)
+	}
+	if _, err := instance.WatchConfigFile(path, 0); !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("interval 0 錯誤 = %v", err)
+	}
+	if _, err := instance.WatchConfigFile(path, time.Second); !errors.Is(err, ErrReloadRejected) {
+		t.Fatalf("首次套用錯誤 = %v，預期 ErrReloadRejected", err)
+	}
+	if _, err := instance.WatchConfigFile(filepath.Join(t.TempDir(), "missing.json"), time.Second); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("缺少檔案錯誤 = %v", err)
+	}
+}
+
+func TestInstanceReloadKeepsRotatingOutputAcrossRotation(t *testing.T) {
+	base := t.TempDir()
+	clock := newManualRotationClock(time.Date(2026, time.July, 29, 23, 0, 0, 0, time.Local))
+	settings, err := resolveFileOutputOptions()
+	if err != nil {
+		t.Fatalf("解析 options 失敗：%v", err)
+	}
+	settings.clock = clock
+	cfg := fileOutputTestConfig(base, "")
+	instance, err := newInstance(cfg.normalizedCopy(), settings)
+	if err != nil {
+		t.Fatalf("建立 Instance 失敗：%v", err)
+	}
+	defer func() { _ = instance.Close() }()
+	fileOut := instance.closers[0]
+	timer := clock.nextTimer(t)
+	instance.Logger().Info("第一天")
+
+	cfg.Format = "console"
+	if err := instance.Reload(cfg); err != nil {
+		t.Fatalf("Reload 失敗：%v", err)
+	}
+	if len(instance.closers) != 1 || instance.closers[0] != fileOut {
+		t.Fatalf("closers = %v，目錄與檔名未變更時應沿用換檔輸出", instance.closers)
+	}
+
+	nextDay := time.Date(2026, time.July, 30, 0, 0, 0, 0, time.Local)
+	clock.setNow(nextDay)
+	timer.fire(nextDay)
+	clock.nextTimer(t)
+	instance.Logger().Info("第二天")
+	if err := instance.Close(); err != nil {
+		t.Fatalf("關閉 Instance 失敗：%v", err)
+	}
+
+	if durations := clock.timerDurations(); len(durations) != 2 {
+		t.Fatalf("換檔 timer = %v，Reload 不應建立第二個換檔 worker", durations)
+	}
+	assertRetainedLogFiles(t, base, "2026-07-29.log", "2026-07-30.log")
+	first := readLogFile(t, filepath.Join(base, "2026-07-29.log"))
+	second := readLogFile(t, filepath.Join(base, "2026-07-30.log"))
+	if !strings.Contains(first, `"msg":"第一天"`) || strings.Contains(first, "第二天") {
+		t.Fatalf("第一天檔案內容 = %q", first)
+	}
+	if !strings.Contains(second, "INFO") || !strings.Contains(second, "第二天") || strings.Contains(second, `"msg"`) {
+		t.Fatalf("第二天檔案內容 = %q，預期為重新載入後的 console 格式", second)
+	}
+}
@@ -0,0 +1,111 @@
+package zlogger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+)
+
+// Reopener 表示可在外部工具改名日誌檔後，以原路徑重新開啟檔案的輸出。
+type Reopener interface {
+	Reopen() error
+}
+
+// Reopen 以目前週期的路徑重新開啟所有分級檔案，供 logrotate 等外部工具改名後使用。
+//
+// 新檔案經由 os.Root 開啟並沿用原本的權限與大小上限，於 mutex 保護下替換 handle，
+// 再關閉舊 handle。開啟失敗時保留原 handle 並回傳錯誤；期間若已依週期換檔，
+// 則保留換檔結果。
+func (s *SplitOutput) Reopen() error {
+	s.mutex.Lock()
+	if s.closed {
+		s.mutex.Unlock()
+		return fmt.Errorf("分級輸出已關閉：%w", os.ErrClosed)
+	}
+	leaves := slices.Clone(s.leaves)
+	opener := s.opener
+	filePerm := s.settings.filePerm
+	s.mutex.Unlock()
+
+	if opener == nil || len(leaves) == 0 {
+		return fmt.Errorf("分級輸出尚未初始化：%w", os.ErrInvalid)
+	}
+	newFiles, err := opener(s.directory, leaves, filePerm)
+	if err != nil {
+		return err
+	}
+	newFiles = s.withBuffer(s.withSizeLimit(newFiles, leaves))
+
+	s.mutex.Lock()
+	if s.closed {
+		s.mutex.Unlock()
+		return errors.Join(
+			fmt.Errorf("分級輸出已關閉：%w", os.ErrClosed),
+			newFiles.close(),
+		)
+	}
+	if !slices.Equal(s.leaves, leaves) {
+		s.mutex.Unlock()
+		return newFiles.close()
+	}
+	previous := s.replaceFiles(newFiles)
+	s.mutex.Unlock()
+
+	s.reportInternalEvents(previous.failures(InternalEventClose, s.directory, leaves, writeSyncCloser.Close))
+	return s.updateCurrentLinks(leaves)
+}
+
+// Reopen 重新開啟 Instance 擁有的所有檔案輸出；console 輸出不受影響。
+func (i *Instance) Reopen() error {
+	if i == nil {
+		return nil
+	}
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	if i.closed {
+		return fmt.Errorf("重新開啟 logger instance: %w", os.ErrClosed)
+	}
+
+	reopenErrs := make([]error, 0, len(i.closers))
+	for _, closer := range i.closers {
+		if reopener, ok := closer.(Reopener); ok {
+			reopenErrs = append(reopenErrs, reopener.Reopen())
+		}
+	}
+	return errors.Join(reopenErrs...)
+}
+
+// ReopenOnSignal 在 signals 每次收到訊號時呼叫 target.Reopen，直到回傳的 stop 被呼叫
+// 或 signals 被關閉。
+//
+// 呼叫端自行以 signal.Notify 註冊訊號，例如 SIGHUP。onError 可為 nil，用於接收
+// Reopen 錯誤。stop 可重複呼叫，並會等待背景 goroutine 結束；stop 不會取消訊號註冊。
+func ReopenOnSignal(target Reopener, signals <-chan os.Signal, onError func(error)) (stop func()) {
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		for {
+			select {
+			case <-done:
+				return
+			case _, ok := <-signals:
+				if !ok {
+					return
+				}
+				if err := target.Reopen(); err != nil && onError != nil {
+					onError(err)
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+		<-exited
+	}
+}
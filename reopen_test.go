@@ -0,0 +1,164 @@
+package zlogger
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSplitOutputReopenAfterRename(t *testing.T) {
+	base := t.TempDir()
+	output := newRetentionTestOutput(t, base, time.Date(2026, time.July, 29, 10, 0, 0, 0, time.Local))
+	t.Cleanup(func() { _ = output.Close() })
+
+	active := filepath.Join(base, "app-warn-2026-07-29.log")
+	if _, err := output.Write(zapcore.WarnLevel, []byte("改名前\n")); err != nil {
+		t.Fatalf("寫入失敗：%v", err)
+	}
+	if err := os.Rename(active, active+".1"); err != nil {
+		t.Fatalf("模擬 logrotate 改名失敗：%v", err)
+	}
+	if err := output.Reopen(); err != nil {
+		t.Fatalf("Reopen 失敗：%v", err)
+	}
+	if _, err := output.Write(zapcore.WarnLevel, []byte("改名後\n")); err != nil {
+		t.Fatalf("寫入失敗：%v", err)
+	}
+	if err := output.Sync(); err != nil {
+		t.Fatalf("同步失敗：%v", err)
+	}
+
+	assertFileContent(t, active+".1", "改名前\n")
+	assertFileContent(t, active, "改名後\n")
+}
+
+func TestSplitOutputReopenKeepsHandleOnSymlink(t *testing.T) {
+	base := t.TempDir()
+	skipWithoutSymlink(t, base)
+	output := newRetentionTestOutput(t, base, time.Date(2026, time.July, 29, 10, 0, 0, 0, time.Local))
+	t.Cleanup(func() { _ = output.Close() })
+
+	active := filepath.Join(base, "app-warn-2026-07-29.log")
+	outside := filepath.Join(t.TempDir(), "outside.log")
+	if err := os.WriteFile(outside, nil, 0o600); err != nil {
+		t.Fatalf("建立外部檔案失敗：%v", err)
+	}
+	if err := os.Rename(active, active+".1"); err != nil {
+		t.Fatalf("改名失敗：%v", err)
+	}
+	if err := os.Symlink(outside, active); err != nil {
+		t.Fatalf("建立 symlink 失敗：%v", err)
+	}
+
+	if err := output.Reopen(); !errors.Is(err, ErrUnsafeLogPath) {
+		t.Fatalf("Reopen 錯誤 = %v，預期 ErrUnsafeLogPath", err)
+	}
+	if _, err := output.Write(zapcore.WarnLevel, []byte("沿用原 handle\n")); err != nil {
+		t.Fatalf("寫入失敗：%v", err)
+	}
+	if err := output.Sync(); err != nil {
+		t.Fatalf("同步失敗：%v", err)
+	}
+	assertFileContent(t, active+".1", "沿用原 handle\n")
+	assertFileContent(t, outside, "")
+}
+
+func TestInstanceReopenFixedFile(t *testing.T) {
+	base := t.TempDir()
+	instance, err := New(fileOutputTestConfig(base, "app.log"))
+	if err != nil {
+		t.Fatalf("建立 Instance 失敗：%v", err)
+	}
+	active := filepath.Join(base, "app.log")
+	if err := os.Rename(active, active+".1"); err != nil {
+		t.Fatalf("模擬 logrotate 改名失敗：%v", err)
+	}
+	if err := instance.Reopen(); err != nil {
+		t.Fatalf("Reopen 失敗：%v", err)
+	}
+	instance.Logger().Info("改名後")
+	if err := instance.Close(); err != nil {
+		t.Fatalf("關閉 Instance 失敗：%v", err)
+	}
+
+	//nolint:gosec // 測試只讀取 t.TempDir 內的預期檔案。
+	content, err := os.ReadFile(active)
+	if err != nil {
+		t.Fatalf("讀取重新開啟的檔案失敗：%v", err)
+	}
+	if !strings.Contains(string(content), "改名後") || strings.Contains(string(content), "logger initialized") {
+		t.Fatalf("重新開啟的檔案內容 = %s", content)
+	}
+	if err := instance.Reopen(); !errors.Is(err, os.ErrClosed) {
+		t.Fatalf("關閉後 Reopen 錯誤 = %v，預期 os.ErrClosed", err)
+	}
+}
+
+func TestSplitOutputReopenAfterClose(t *testing.T) {
+	output, err := NewSplitOutput(t.TempDir(), "app")
+	if err != nil {
+		t.Fatalf("建立 SplitOutput 失敗：%v", err)
+	}
+	if err := output.Close(); err != nil {
+		t.Fatalf("關閉 SplitOutput 失敗：%v", err)
+	}
+	if err := output.Reopen(); !errors.Is(err, os.ErrClosed) {
+		t.Fatalf("關閉後 Reopen 錯誤 = %v，預期 os.ErrClosed", err)
+	}
+}
+
+type countingReopener struct {
+	calls atomic.Int32
+	err   error
+}
+
+func (r *countingReopener) Reopen() error {
+	r.calls.Add(1)
+	return r.err
+}
+
+func TestReopenOnSignal(t *testing.T) {
+	reopenErr := errors.New("重新開啟失敗")
+	target := &countingReopener{err: reopenErr}
+	signals := make(chan os.Signal)
+	errs := make(chan error, 1)
+	stop := ReopenOnSignal(target, signals, func(err error) { errs <- err })
+
+	signals <- os.Interrupt
+	select {
+	case err := <-errs:
+		if !errors.Is(err, reopenErr) {
+			t.Fatalf("onError 收到 %v，預期 %v", err, reopenErr)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("等待 Reopen 逾時")
+	}
+	stop()
+	stop()
+	if calls := target.calls.Load(); calls != 1 {
+		t.Fatalf("Reopen 呼叫 %d 次，預期 1", calls)
+	}
+}
+
+func TestReopenOnSignalStopsWhenChannelClosed(t *testing.T) {
+	signals := make(chan os.Signal)
+	stop := ReopenOnSignal(&countingReopener{}, signals, nil)
+	close(signals)
+
+	stopped := make(chan struct{})
+	go func() {
+		stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("signals 關閉後 goroutine 應結束")
+	}
+}
@@ -0,0 +1,123 @@
+package zlogger
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const (
+	compressedLogSuffix     = ".gz"
+	compressingLogTmpSuffix = ".gz.tmp"
+)
+
+// compressRootedLogFile 在 baseDir 的 os.Root 內將 leaf 壓縮為 leaf.gz。
+//
+// 壓縮內容先寫入暫存檔並同步，再原子改名為目標檔並同步目錄，成功後才刪除原檔。
+// leaf 已不存在時視為已處理；leaf 為 symlink 或目標已存在時拒絕覆寫。
+func compressRootedLogFile(baseDir, leaf string, filePerm os.FileMode) error {
+	target := leaf + compressedLogSuffix
+	temporary := leaf + compressingLogTmpSuffix
+	for _, name := range []string{leaf, target, temporary} {
+		if err := validateLogLeaf(name, false); err != nil {
+			return err
+		}
+	}
+
+	return withLogRoot(baseDir, func(root *os.Root) error {
+		info, err := root.Lstat(leaf)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("檢查日誌 root %q 的 leaf %q: %w", baseDir, leaf, err)
+		}
+		if !info.Mode().IsRegular() {
+			return fmt.Errorf("%w: 日誌 root %q 的 leaf %q 不是一般檔案", ErrUnsafeLogPath, baseDir, leaf)
+		}
+		if _, err := root.Lstat(target); err == nil {
+			return fmt.Errorf("壓縮日誌 root %q 的 leaf %q: 目標 %q 已存在: %w", baseDir, leaf, target, os.ErrExist)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("檢查日誌 root %q 的壓縮目標 %q: %w", baseDir, target, err)
+		}
+		if err := removeRootedLogFile(root, baseDir, temporary); err != nil {
+			return err
+		}
+
+		if err := writeCompressedLog(root, baseDir, leaf, temporary, info, filePerm); err != nil {
+			return errors.Join(err, removeRootedLogFile(root, baseDir, temporary))
+		}
+		if err := root.Rename(temporary, target); err != nil {
+			return errors.Join(
+				fmt.Errorf("改名日誌 root %q 的壓縮檔 %q 為 %q: %w", baseDir, temporary, target, err),
+				removeRootedLogFile(root, baseDir, temporary),
+			)
+		}
+		if err := syncRootedDir(root, baseDir); err != nil {
+			return err
+		}
+		return removeRootedLogFile(root, baseDir, leaf)
+	})
+}
+
+func writeCompressedLog(
+	root *os.Root,
+	baseDir string,
+	leaf string,
+	temporary string,
+	expected os.FileInfo,
+	filePerm os.FileMode,
+) (err error) {
+	source, err := root.OpenFile(leaf, os.O_RDONLY, 0)
+	if err != nil {
+		return fmt.Errorf("開啟日誌 root %q 的 leaf %q: %w", baseDir, leaf, err)
+	}
+	defer func() {
+		if closeErr := source.Close(); closeErr != nil {
+			err = errors.Join(err, fmt.Errorf("關閉日誌 root %q 的 leaf %q: %w", baseDir, leaf, closeErr))
+		}
+	}()
+	opened, err := source.Stat()
+	if err != nil {
+		return fmt.Errorf("檢查日誌 root %q 的 leaf %q: %w", baseDir, leaf, err)
+	}
+	if !os.SameFile(expected, opened) {
+		return fmt.Errorf("%w: 日誌 root %q 的 leaf %q 在壓縮前被替換", ErrUnsafeLogPath, baseDir, leaf)
+	}
+
+	destination, err := root.OpenFile(temporary, os.O_CREATE|os.O_EXCL|os.O_WRONLY, filePerm)
+	if err != nil {
+		return fmt.Errorf("建立日誌 root %q 的壓縮暫存檔 %q: %w", baseDir, temporary, err)
+	}
+
+	writer := gzip.NewWriter(destination)
+	writer.Name = leaf
+	writer.ModTime = expected.ModTime()
+	_, copyErr := io.Copy(writer, source)
+	writeErrs := []error{copyErr, writer.Close(), destination.Sync()}
+	if closeErr := destination.Close(); closeErr != nil {
+		writeErrs = append(writeErrs, closeErr)
+	}
+	if err := errors.Join(writeErrs...); err != nil {
+		return fmt.Errorf("寫入日誌 root %q 的壓縮暫存檔 %q: %w", baseDir, temporary, err)
+	}
+	return nil
+}
+
+// compressLater 將已關閉的檔案排入背景壓縮；未啟用壓縮或已關閉時略過。
+func (s *SplitOutput) compressLater(leaves ...string) {
+	if s.compressor == nil {
+		return
+	}
+	directory := s.directory
+	filePerm := s.settings.filePerm
+	for _, leaf := range leaves {
+		s.compressor.enqueue(func() {
+			err := compressRootedLogFile(directory, leaf, filePerm)
+			s.settings.reportInternalEvent(InternalEventCompression, filepath.Join(directory, leaf), err)
+		})
+	}
+}
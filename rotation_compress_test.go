@@ -0,0 +1,262 @@
+package zlogger
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSplitOutputCompressesPreviousDayAfterRotation(t *testing.T) {
+	base := t.TempDir()
+	now := time.Date(2026, time.July, 29, 10, 0, 0, 0, time.Local)
+	clock := newManualRotationClock(now)
+	settings, err := resolveFileOutputOptions(WithCompression(), WithFilePerm(0o640))
+	if err != nil {
+		t.Fatalf("解析壓縮 option 失敗：%v", err)
+	}
+	output, err := newSplitOutputWithSettings(base, "app", clock, openSplitFilesWithPermissions, settings)
+	if err != nil {
+		t.Fatalf("建立 SplitOutput 失敗：%v", err)
+	}
+	if _, err := output.Write(zapcore.InfoLevel, []byte("昨天的 info\n")); err != nil {
+		t.Fatalf("寫入 info 失敗：%v", err)
+	}
+
+	timer := clock.nextTimer(t)
+	nextDay := now.AddDate(0, 0, 1)
+	clock.setNow(nextDay)
+	timer.fire(nextDay)
+	clock.nextTimer(t)
+	if err := output.Close(); err != nil {
+		t.Fatalf("關閉 SplitOutput 失敗：%v", err)
+	}
+
+	date := now.Format(dailyRotationLayout)
+	nextDate := nextDay.Format(dailyRotationLayout)
+	assertRetainedLogFiles(t, base,
+		"app-error-"+date+".log.gz",
+		"app-error-"+nextDate+".log",
+		"app-info-"+date+".log.gz",
+		"app-info-"+nextDate+".log",
+		"app-warn-"+date+".log.gz",
+		"app-warn-"+nextDate+".log",
+	)
+	assertGzipContent(t, filepath.Join(base, "app-info-"+date+".log.gz"), "昨天的 info\n")
+	assertGzipContent(t, filepath.Join(base, "app-warn-"+date+".log.gz"), "")
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(filepath.Join(base, "app-info-"+date+".log.gz"))
+		if err != nil {
+			t.Fatalf("讀取壓縮檔 mode 失敗：%v", err)
+		}
+		if got := info.Mode().Perm(); got&^0o640 != 0 {
+			t.Fatalf("壓縮檔 mode = %04o，不得超出設定的 0640", got)
+		}
+	}
+}
+
+func TestSplitOutputCompressesSizeBackups(t *testing.T) {
+	base := t.TempDir()
+	now := time.Date(2026, time.July, 29, 10, 0, 0, 0, time.Local)
+	settings, err := resolveFileOutputOptions(WithCompression(), WithMaxSize(8))
+	if err != nil {
+		t.Fatalf("解析壓縮 option 失敗：%v", err)
+	}
+	output, err := newSplitOutputWithSettings(
+		base,
+		"app",
+		newManualRotationClock(now),
+		openSplitFilesWithPermissions,
+		settings,
+	)
+	if err != nil {
+		t.Fatalf("建立 SplitOutput 失敗：%v", err)
+	}
+	for _, message := range []string{"first\n", "second\n", "third\n"} {
+		if _, err := output.Write(zapcore.ErrorLevel, []byte(message)); err != nil {
+			t.Fatalf("寫入 %q 失敗：%v", message, err)
+		}
+	}
+	if err := output.Close(); err != nil {
+		t.Fatalf("關閉 SplitOutput 失敗：%v", err)
+	}
+
+	date := now.Format(dailyRotationLayout)
+	assertGzipContent(t, filepath.Join(base, "app-error-"+date+"-1.log.gz"), "first\n")
+	assertGzipContent(t, filepath.Join(base, "app-error-"+date+"-2.log.gz"), "second\n")
+	assertFileContent(t, filepath.Join(base, "app-error-"+date+".log"), "third\n")
+	if _, err := os.Stat(filepath.Join(base, "app-error-"+date+"-1.log")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("壓縮成功後應刪除原備份，Stat 錯誤 = %v", err)
+	}
+}
+
+func TestSplitOutputRemovesStaleCompressionTemporariesAtStartup(t *testing.T) {
+	base := t.TempDir()
+	createRetentionFixtures(t, base,
+		"app-info-2026-07-28.log",
+		"app-info-2026-07-28.log.gz.tmp",
+		"app-warn-2026-07-27-1.log.gz.tmp",
+		"other-info-2026-07-28.log.gz.tmp",
+		"notes.gz.tmp",
+	)
+
+	now := time.Date(2026, time.July, 29, 10, 0, 0, 0, time.Local)
+	output := newRetentionTestOutput(t, base, now)
+	if err := output.Close(); err != nil {
+		t.Fatalf("關閉 SplitOutput 失敗：%v", err)
+	}
+
+	assertRetainedLogFiles(t, base,
+		"app-error-2026-07-29.log",
+		"app-info-2026-07-28.log",
+		"app-info-2026-07-29.log",
+		"app-warn-2026-07-29.log",
+		"notes.gz.tmp",
+		"other-info-2026-07-28.log.gz.tmp",
+	)
+}
+
+func TestCompressRootedLogFileRefusesUnsafeTargets(t *testing.T) {
+	t.Run("existing target", func(t *testing.T) {
+		base := t.TempDir()
+		createRetentionFixtures(t, base, "app.log", "app.log.gz")
+
+		err := compressRootedLogFile(base, "app.log", defaultLogFileMode)
+		if !errors.Is(err, os.ErrExist) {
+			t.Fatalf("錯誤 = %v，預期 os.ErrExist", err)
+		}
+		assertFileContent(t, filepath.Join(base, "app.log"), "app.log\n")
+		assertFileContent(t, filepath.Join(base, "app.log.gz"), "app.log.gz\n")
+	})
+
+	t.Run("symlink leaf", func(t *testing.T) {
+		parent := t.TempDir()
+		base := filepath.Join(parent, "logs")
+		if err := os.Mkdir(base, 0o700); err != nil {
+			t.Fatalf("建立 base 失敗：%v", err)
+		}
+		outside := filepath.Join(parent, "outside.log")
+		if err := os.WriteFile(outside, []byte("外部\n"), 0o600); err != nil {
+			t.Fatalf("建立外部檔案失敗：%v", err)
+		}
+		if err := os.Symlink(outside, filepath.Join(base, "app.log")); err != nil {
+			t.Skipf("平台無法建立 symlink：%v", err)
+		}
+
+		err := compressRootedLogFile(base, "app.log", defaultLogFileMode)
+		if !errors.Is(err, ErrUnsafeLogPath) {
+			t.Fatalf("錯誤 = %v，預期 ErrUnsafeLogPath", err)
+		}
+		assertFileContent(t, outside, "外部\n")
+		assertPathDoesNotExist(t, filepath.Join(base, "app.log.gz"))
+	})
+
+	t.Run("missing leaf", func(t *testing.T) {
+		if err := compressRootedLogFile(t.TempDir(), "app.log", defaultLogFileMode); err != nil {
+			t.Fatalf("已不存在的檔案應視為已處理：%v", err)
+		}
+	})
+}
+
+func TestNumberedBackupSkipsCompressedIndex(t *testing.T) {
+	base := t.TempDir()
+	createRetentionFixtures(t, base, "app.log", "app-1.log.gz")
+
+	backup, err := rollRootedLogFile(base, "app.log")
+	if err != nil {
+		t.Fatalf("換檔失敗：%v", err)
+	}
+	if backup != "app-2.log" {
+		t.Fatalf("備份名稱 = %q，預期略過已壓縮的 app-1", backup)
+	}
+}
+
+func TestBackgroundQueueDrainsOnClose(t *testing.T) {
+	queue := newBackgroundQueue(0)
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var order []int
+	for index := range 3 {
+		queue.enqueue(func() {
+			if index == 0 {
+				<-release
+			}
+			mu.Lock()
+			order = append(order, index)
+			mu.Unlock()
+		})
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		queue.close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+		t.Fatal("close 不應在工作完成前回傳")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(release)
+	<-closed
+	queue.close()
+
+	if queue.enqueue(func() {}) {
+		t.Fatal("關閉後不應接受新工作")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(order) != 3 || order[0] != 0 || order[1] != 1 || order[2] != 2 {
+		t.Fatalf("工作執行順序 = %v，預期 [0 1 2]", order)
+	}
+}
+
+func TestBackgroundQueueLimitAndCloseDeadline(t *testing.T) {
+	queue := newBackgroundQueue(2)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	queue.enqueue(func() {
+		close(started)
+		<-release
+	})
+	<-started
+	for index := range 3 {
+		if queued := queue.enqueue(func() {}); queued != (index < 2) {
+			t.Fatalf("第 %d 筆 enqueue = %t，預期只接受 limit 內的工作", index, queued)
+		}
+	}
+
+	abandoned, ok := queue.closeWithin(20 * time.Millisecond)
+	if ok || abandoned != 2 {
+		t.Fatalf("closeWithin = %d, %t，預期逾時並捨棄 2 筆", abandoned, ok)
+	}
+}
+
+func assertGzipContent(t *testing.T, path, want string) {
+	t.Helper()
+	//nolint:gosec // helper 只接收測試建立於 t.TempDir 的預期路徑。
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("開啟壓縮檔 %q 失敗：%v", path, err)
+	}
+	defer func() { _ = file.Close() }()
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("讀取 gzip header %q 失敗：%v", path, err)
+	}
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("解壓縮 %q 失敗：%v", path, err)
+	}
+	if string(content) != want {
+		t.Fatalf("壓縮檔 %q 內容 = %q，預期 %q", path, content, want)
+	}
+}
@@ -0,0 +1,111 @@
+package zlogger
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+)
+
+// ErrRotationHookDropped 表示換檔事件未交給 hook：hook queue 已滿，或 Close 等待逾時。
+var ErrRotationHookDropped = errors.New("換檔 hook 事件已略過")
+
+const (
+	// rotationHookQueueSize 是尚未執行的換檔事件上限，超過時略過新事件。
+	rotationHookQueueSize = 64
+	// rotationHookCloseTimeout 是 Close 等待 hook 完成的上限。
+	rotationHookCloseTimeout = time.Second
+)
+
+// RotationEvent 描述一次依週期完成的換檔。
+//
+// ClosedFiles 為已關閉的前一週期檔案完整路徑；啟用 WithCompression 時，壓縮成功的
+// 檔案以 .gz 路徑回報。NewFiles 為換檔後開始寫入的檔案完整路徑，順序同路由表。
+type RotationEvent struct {
+	ClosedFiles []string
+	NewFiles    []string
+	Time        time.Time
+}
+
+// RotationHook 在換檔完成後接收 RotationEvent。
+type RotationHook func(RotationEvent)
+
+// WithOnRotate 註冊換檔完成後執行的 hook，可重複使用以註冊多個 hook。
+//
+// hook 在前一週期的檔案關閉（以及啟用時的壓縮）完成後，於背景 goroutine 依註冊順序
+// 執行，不會阻塞寫入或換檔；hook panic 會被攔截並回報，不影響後續 hook。啟動時的
+// 首次開檔與 WithMaxSize 的編號備份不觸發 hook。
+//
+// 尚未執行的事件最多保留 64 筆，超過時略過新事件。Close 最多等待 1 秒讓已排入的 hook
+// 完成，逾時後捨棄尚未開始的事件，執行中的 hook 不會被中斷。兩者都以 InternalEventHook
+// 與包裝 ErrRotationHookDropped 的錯誤回報。
+func WithOnRotate(hook RotationHook) FileOutputOption {
+	return fileOutputOptionFunc(func(settings *fileOutputSettings) error {
+		if hook == nil {
+			return fmt.Errorf("%w: OnRotate hook 不可為 nil", ErrInvalidRotation)
+		}
+		settings.rotationHooks = append(settings.rotationHooks, hook)
+		return nil
+	})
+}
+
+// finishRotation 在前一週期檔案關閉後排入壓縮與 hook；壓縮完成後才執行 hook。
+func (s *SplitOutput) finishRotation(closed, opened []string, at time.Time) {
+	if s.compressor == nil {
+		s.notifyRotation(s.logPaths(closed), opened, at)
+		return
+	}
+	directory := s.directory
+	filePerm := s.settings.filePerm
+	s.compressor.enqueue(func() {
+		paths := make([]string, len(closed))
+		for index, leaf := range closed {
+			if err := compressRootedLogFile(directory, leaf, filePerm); err != nil {
+				s.settings.reportInternalEvent(InternalEventCompression, filepath.Join(directory, leaf), err)
+			} else {
+				leaf += compressedLogSuffix
+			}
+			paths[index] = filepath.Join(directory, leaf)
+		}
+		s.notifyRotation(paths, opened, at)
+	})
+}
+
+// notifyRotation 將 hook 排入獨立的 hook queue，避免慢速 hook 延遲壓縮或寫入。
+func (s *SplitOutput) notifyRotation(closedPaths, openedLeaves []string, at time.Time) {
+	if s.hooks == nil || len(closedPaths) == 0 {
+		return
+	}
+	event := RotationEvent{ClosedFiles: closedPaths, NewFiles: s.logPaths(openedLeaves), Time: at}
+	settings := s.settings
+	queued := s.hooks.enqueue(func() {
+		for _, hook := range settings.rotationHooks {
+			settings.reportInternalEvent(InternalEventHook, "", runRotationHook(hook, event))
+		}
+	})
+	if !queued {
+		settings.reportInternalEvent(InternalEventHook, "", fmt.Errorf(
+			"%w: hook queue 已滿，略過 %s 的換檔事件", ErrRotationHookDropped, at.Format(time.RFC3339)))
+	}
+}
+
+// runRotationHook 以獨立的 event 複本執行 hook，並將 panic 轉為錯誤。
+func runRotationHook(hook RotationHook, event RotationEvent) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("換檔 hook panic: %v", recovered)
+		}
+	}()
+	event.ClosedFiles = append([]string(nil), event.ClosedFiles...)
+	event.NewFiles = append([]string(nil), event.NewFiles...)
+	hook(event)
+	return nil
+}
+
+func (s *SplitOutput) logPaths(leaves []string) []string {
+	paths := make([]string, len(leaves))
+	for index, leaf := range leaves {
+		paths[index] = filepath.Join(s.directory, leaf)
+	}
+	return paths
+}
@@ -0,0 +1,188 @@
+package zlogger
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestWithOnRotateRejectsNilHook(t *testing.T) {
+	base := filepath.Join(t.TempDir(), "不應建立")
+	output, err := NewSplitOutputWithOptions(base, "app", WithOnRotate(nil))
+	if output != nil {
+		_ = output.Close()
+		t.Fatal("nil hook 不應回傳 SplitOutput")
+	}
+	if !errors.Is(err, ErrInvalidRotation) {
+		t.Fatalf("錯誤 = %v，預期 ErrInvalidRotation", err)
+	}
+	assertPathDoesNotExist(t, base)
+}
+
+func TestSplitOutputOnRotateReceivesEvent(t *testing.T) {
+	base := t.TempDir()
+	events := make(chan RotationEvent, 2)
+	output, clock := newHookTestOutput(t, base,
+		WithOnRotate(func(RotationEvent) { panic("hook 故障") }),
+		WithOnRotate(func(event RotationEvent) { events <- event }),
+	)
+
+	nextDay := rotateHookTestOutput(t, clock)
+	event := receiveRotationEvent(t, events)
+	if !event.Time.Equal(nextDay) {
+		t.Fatalf("Time = %s，預期 %s", event.Time, nextDay)
+	}
+	want := func(date string) []string {
+		return []string{
+			filepath.Join(base, "app-info-"+date+".log"),
+			filepath.Join(base, "app-warn-"+date+".log"),
+			filepath.Join(base, "app-error-"+date+".log"),
+		}
+	}
+	if !slices.Equal(event.ClosedFiles, want("2026-07-29")) {
+		t.Fatalf("ClosedFiles = %v，預期 %v", event.ClosedFiles, want("2026-07-29"))
+	}
+	if !slices.Equal(event.NewFiles, want("2026-07-30")) {
+		t.Fatalf("NewFiles = %v，預期 %v", event.NewFiles, want("2026-07-30"))
+	}
+	if err := output.Close(); err != nil {
+		t.Fatalf("關閉 SplitOutput 失敗：%v", err)
+	}
+}
+
+func TestSplitOutputOnRotateRunsAfterCompression(t *testing.T) {
+	base := t.TempDir()
+	events := make(chan RotationEvent, 1)
+	output, clock := newHookTestOutput(t, base,
+		WithCompression(),
+		WithOnRotate(func(event RotationEvent) { events <- event }),
+	)
+	if _, err := output.Write(zapcore.WarnLevel, []byte("第一天\n")); err != nil {
+		t.Fatalf("寫入失敗：%v", err)
+	}
+
+	rotateHookTestOutput(t, clock)
+	event := receiveRotationEvent(t, events)
+	closedWarn := filepath.Join(base, "app-warn-2026-07-29.log"+compressedLogSuffix)
+	if !slices.Contains(event.ClosedFiles, closedWarn) {
+		t.Fatalf("ClosedFiles = %v，預期包含 %s", event.ClosedFiles, closedWarn)
+	}
+	assertGzipContent(t, closedWarn, "第一天\n")
+	if err := output.Close(); err != nil {
+		t.Fatalf("關閉 SplitOutput 失敗：%v", err)
+	}
+}
+
+func TestSplitOutputSlowHookDoesNotBlockWrites(t *testing.T) {
+	base := t.TempDir()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	finished := make(chan struct{})
+	output, clock := newHookTestOutput(t, base, WithOnRotate(func(RotationEvent) {
+		close(started)
+		<-release
+		close(finished)
+	}))
+
+	rotateHookTestOutput(t, clock)
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("等待換檔 hook 開始逾時")
+	}
+	if _, err := output.Write(zapcore.InfoLevel, []byte("hook 執行中\n")); err != nil {
+		t.Fatalf("hook 執行中寫入失敗：%v", err)
+	}
+	if err := output.Sync(); err != nil {
+		t.Fatalf("hook 執行中同步失敗：%v", err)
+	}
+	assertFileContent(t, filepath.Join(base, "app-info-2026-07-30.log"), "hook 執行中\n")
+
+	close(release)
+	if err := output.Close(); err != nil {
+		t.Fatalf("關閉 SplitOutput 失敗：%v", err)
+	}
+	select {
+	case <-finished:
+	default:
+		t.Fatal("Close 應等待已排入的 hook 完成")
+	}
+}
+
+func TestSplitOutputCloseStopsWaitingForHungHook(t *testing.T) {
+	base := t.TempDir()
+	handler := &recordingErrorHandler{}
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	output, clock := newHookTestOutput(t, base,
+		WithErrorHandler(handler.handle),
+		WithOnRotate(func(RotationEvent) {
+			close(started)
+			<-release
+		}),
+	)
+
+	rotateHookTestOutput(t, clock)
+	<-started
+	closed := make(chan error, 1)
+	go func() { closed <- output.Close() }()
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Fatalf("關閉 SplitOutput 失敗：%v", err)
+		}
+	case <-time.After(rotationHookCloseTimeout + 5*time.Second):
+		t.Fatal("hook 卡住時 Close 應在逾時後回傳")
+	}
+
+	events := handler.snapshot()
+	if len(events) != 1 || events[0].Kind != InternalEventHook || !errors.Is(events[0].Err, ErrRotationHookDropped) {
+		t.Fatalf("events = %+v，預期回報 hook 等待逾時", events)
+	}
+}
+
+func newHookTestOutput(
+	t *testing.T,
+	base string,
+	opts ...FileOutputOption,
+) (*SplitOutput, *manualRotationClock) {
+	t.Helper()
+	clock := newManualRotationClock(time.Date(2026, time.July, 29, 23, 0, 0, 0, time.Local))
+	settings, err := resolveFileOutputOptions(opts...)
+	if err != nil {
+		t.Fatalf("解析 options 失敗：%v", err)
+	}
+	output, err := newSplitOutputWithSettings(base, "app", clock, openSplitFilesWithPermissions, settings)
+	if err != nil {
+		t.Fatalf("建立 SplitOutput 失敗：%v", err)
+	}
+	t.Cleanup(func() { _ = output.Close() })
+	return output, clock
+}
+
+// rotateHookTestOutput 觸發換日並等待下一個 timer，回傳換檔時間。
+func rotateHookTestOutput(t *testing.T, clock *manualRotationClock) time.Time {
+	t.Helper()
+	timer := clock.nextTimer(t)
+	nextDay := time.Date(2026, time.July, 30, 0, 0, 0, 0, time.Local)
+	clock.setNow(nextDay)
+	timer.fire(nextDay)
+	clock.nextTimer(t)
+	return nextDay
+}
+
+func receiveRotationEvent(t *testing.T, events <-chan RotationEvent) RotationEvent {
+	t.Helper()
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("等待換檔 hook 逾時")
+		return RotationEvent{}
+	}
+}
@@ -0,0 +1,154 @@
+package zlogger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	defaultSplitCurrentLinkTemplate = "{prefix}-{level}.log"
+	defaultCurrentLinkTemplate      = "current.log"
+	currentLinkTmpSuffix            = ".tmp"
+)
+
+// WithCurrentLink 在日誌目錄內維護指向目前檔案的固定名稱 symlink。
+//
+// template 使用與 WithFileNameTemplate 相同的 token，但不可包含日期；空字串時
+// SplitOutput 使用 {prefix}-{level}.log，單一檔案輸出使用 current.log。多個 route 時
+// 樣板必須包含 {level}。每次換檔會在同一個 os.Root 內建立暫存 symlink 再原子改名；
+// 寫入仍只開啟日期檔，既有的同名一般檔案不會被覆寫。
+func WithCurrentLink(template string) FileOutputOption {
+	return fileOutputOptionFunc(func(settings *fileOutputSettings) error {
+		settings.currentLink = nil
+		if template != "" {
+			parsed, err := parseFileNameTemplate(template)
+			if err != nil {
+				return err
+			}
+			settings.currentLink = parsed
+		}
+		settings.currentLinkEnabled = true
+		return nil
+	})
+}
+
+// currentLinkTemplate 回傳 symlink 名稱樣板；未啟用時回傳 nil。
+func (s fileOutputSettings) currentLinkTemplate(fallback string) (*fileNameTemplate, error) {
+	if !s.currentLinkEnabled {
+		return nil, nil
+	}
+	if s.currentLink != nil {
+		return s.currentLink, nil
+	}
+	return parseFileNameTemplate(fallback)
+}
+
+// validateCurrentLink 確認 symlink 名稱不隨週期變動，且各 route 名稱不同。
+func (t *fileNameTemplate) validateCurrentLink(routeCount int) error {
+	if t.uses(fileNameDate) {
+		return fmt.Errorf("%w: symlink 樣板 %q 不可包含日期", ErrInvalidFileNameTemplate, t.source)
+	}
+	if routeCount > 1 && !t.uses(fileNameLevel) {
+		return fmt.Errorf("%w: 分級輸出的 symlink 樣板 %q 必須包含 {level}", ErrInvalidFileNameTemplate, t.source)
+	}
+	return nil
+}
+
+// currentLinks render 各 route 的 symlink leaf name，順序同 routes。
+func (s *SplitOutput) currentLinks() ([]string, error) {
+	values, err := s.currentLink.values(s.filePrefix)
+	if err != nil {
+		return nil, err
+	}
+	links := make([]string, len(s.routes))
+	for index, route := range s.routes {
+		values.level = route.Name
+		link, err := s.currentLink.renderLeaf(values, time.Time{}, s.settings.schedule.layout)
+		if err != nil {
+			return nil, err
+		}
+		links[index] = link
+	}
+	return links, nil
+}
+
+// updateCurrentLinks 將各 route 的 symlink 指向 leaves；未啟用時不做任何事。
+func (s *SplitOutput) updateCurrentLinks(leaves []string) error {
+	if s.currentLink == nil {
+		return nil
+	}
+	links, err := s.currentLinks()
+	if err != nil {
+		return err
+	}
+	linkErrs := make([]error, 0, len(links))
+	for index, link := range links {
+		linkErrs = append(linkErrs, replaceRootedSymlink(s.directory, link, leaves[index]))
+	}
+	return errors.Join(linkErrs...)
+}
+
+// replaceRootedSymlink 在 baseDir 的 os.Root 內將 link 原子替換為指向 target 的 symlink。
+//
+// 先建立 link.tmp 再改名覆蓋，讀取端不會看到 link 不存在的空窗。link 已存在但不是
+// symlink 時拒絕覆寫；target 只能是同目錄的 leaf name，且不可與 link 相同。
+func replaceRootedSymlink(baseDir, link, target string) error {
+	temporary := link + currentLinkTmpSuffix
+	for _, name := range []string{link, target, temporary} {
+		if err := validateLogLeaf(name, false); err != nil {
+			return err
+		}
+	}
+	if link == target {
+		return fmt.Errorf("%w: symlink %q 不可指向自身", ErrUnsafeLogPath, link)
+	}
+
+	return withLogRoot(baseDir, func(root *os.Root) error {
+		info, err := root.Lstat(link)
+		switch {
+		case err == nil && info.Mode()&os.ModeSymlink == 0:
+			return fmt.Errorf("%w: 日誌 root %q 的 leaf %q 已存在且不是 symlink", ErrUnsafeLogPath, baseDir, link)
+		case err == nil:
+			current, readErr := root.Readlink(link)
+			if readErr == nil && current == target {
+				return nil
+			}
+		case !errors.Is(err, os.ErrNotExist):
+			return fmt.Errorf("檢查日誌 root %q 的 leaf %q: %w", baseDir, link, err)
+		}
+
+		if err := removeRootedSymlink(root, baseDir, temporary); err != nil {
+			return err
+		}
+		if err := root.Symlink(target, temporary); err != nil {
+			return fmt.Errorf("建立日誌 root %q 的 symlink %q: %w", baseDir, temporary, err)
+		}
+		if err := root.Rename(temporary, link); err != nil {
+			return errors.Join(
+				fmt.Errorf("改名日誌 root %q 的 symlink %q 為 %q: %w", baseDir, temporary, link, err),
+				removeRootedSymlink(root, baseDir, temporary),
+			)
+		}
+		return nil
+	})
+}
+
+// removeRootedSymlink 刪除殘留的暫存 symlink；已不存在時視為成功，其他檔案類型一律拒絕。
+func removeRootedSymlink(root *os.Root, baseDir, leaf string) error {
+	info, err := root.Lstat(leaf)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("檢查日誌 root %q 的 leaf %q: %w", baseDir, leaf, err)
+	}
+	if info.Mode()&os.ModeSymlink == 0 {
+		return fmt.Errorf("%w: 日誌 root %q 的 leaf %q 不是 symlink", ErrUnsafeLogPath, baseDir, leaf)
+	}
+	if err := root.Remove(leaf); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("刪除日誌 root %q 的 leaf %q: %w", baseDir, leaf, err)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package zlogger
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSplitOutputCurrentLinkFollowsRotation(t *testing.T) {
+	base := t.TempDir()
+	skipWithoutSymlink(t, base)
+	now := time.Date(2026, time.July, 29, 23, 0, 0, 0, time.Local)
+	clock := newManualRotationClock(now)
+	settings, err := resolveFileOutputOptions(WithCurrentLink(""))
+	if err != nil {
+		t.Fatalf("解析 options 失敗：%v", err)
+	}
+	output, err := newSplitOutputWithSettings(base, "app", clock, openSplitFilesWithPermissions, settings)
+	if err != nil {
+		t.Fatalf("建立 SplitOutput 失敗：%v", err)
+	}
+	t.Cleanup(func() { _ = output.Close() })
+
+	for _, level := range []string{"info", "warn", "error"} {
+		assertCurrentLink(t, base, "app-"+level+".log", "app-"+level+"-2026-07-29.log")
+	}
+
+	timer := clock.nextTimer(t)
+	nextDay := time.Date(2026, time.July, 30, 0, 0, 0, 0, time.Local)
+	clock.setNow(nextDay)
+	timer.fire(nextDay)
+	clock.nextTimer(t)
+
+	if _, err := output.Write(zapcore.WarnLevel, []byte("第二天\n")); err != nil {
+		t.Fatalf("寫入失敗：%v", err)
+	}
+	if err := output.Sync(); err != nil {
+		t.Fatalf("同步失敗：%v", err)
+	}
+	for _, level := range []string{"info", "warn", "error"} {
+		assertCurrentLink(t, base, "app-"+level+".log", "app-"+level+"-2026-07-30.log")
+	}
+	assertFileContent(t, filepath.Join(base, "app-warn.log"), "第二天\n")
+	assertPathDoesNotExist(t, filepath.Join(base, "app-warn.log"+currentLinkTmpSuffix))
+}
+
+func TestSplitOutputCurrentLinkKeepsRegularFile(t *testing.T) {
+	base := t.TempDir()
+	skipWithoutSymlink(t, base)
+	const original = "既有內容\n"
+	if err := os.WriteFile(filepath.Join(base, "app-warn.log"), []byte(original), 0o600); err != nil {
+		t.Fatalf("建立既有檔案失敗：%v", err)
+	}
+
+	output, err := NewSplitOutputWithOptions(base, "app", WithCurrentLink(""))
+	if err == nil {
+		_ = output.Close()
+		t.Fatal("symlink 名稱已被一般檔案佔用時應回傳錯誤")
+	}
+	if !errors.Is(err, ErrUnsafeLogPath) {
+		t.Fatalf("錯誤 = %v，預期 ErrUnsafeLogPath", err)
+	}
+	assertFileContent(t, filepath.Join(base, "app-warn.log"), original)
+}
+
+func TestWithCurrentLinkRejectsInvalidTemplates(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+	}{
+		{name: "包含日期", template: "{prefix}-{level}-{date}.log"},
+		{name: "多個 route 缺少 level", template: "{prefix}.log"},
+		{name: "包含路徑", template: "current/{level}.log"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := filepath.Join(t.TempDir(), "不應建立")
+			output, err := NewSplitOutputWithOptions(base, "app", WithCurrentLink(tt.template))
+			if output != nil {
+				_ = output.Close()
+				t.Fatal("無效 symlink 樣板不應回傳 SplitOutput")
+			}
+			if !errors.Is(err, ErrInvalidFileNameTemplate) {
+				t.Fatalf("錯誤 = %v，預期 ErrInvalidFileNameTemplate", err)
+			}
+			assertPathDoesNotExist(t, base)
+		})
+	}
+}
+
+func TestNewFileOutputCurrentLink(t *testing.T) {
+	base := t.TempDir()
+	skipWithoutSymlink(t, base)
+	instance, err := NewWithOptions(fileOutputTestConfig(base, ""), WithCurrentLink(""))
+	if err != nil {
+		t.Fatalf("建立 Instance 失敗：%v", err)
+	}
+	instance.Logger().Info("經由 current.log 讀取")
+	if err := instance.Close(); err != nil {
+		t.Fatalf("關閉 Instance 失敗：%v", err)
+	}
+
+	assertCurrentLink(t, base, "current.log", time.Now().Format(dailyRotationLayout)+".log")
+	//nolint:gosec // 測試只讀取 t.TempDir 內的預期檔案。
+	content, err := os.ReadFile(filepath.Join(base, "current.log"))
+	if err != nil {
+		t.Fatalf("讀取 current.log 失敗：%v", err)
+	}
+	if !strings.Contains(string(content), "經由 current.log 讀取") {
+		t.Fatalf("current.log 內容 = %s", content)
+	}
+}
+
+func skipWithoutSymlink(t *testing.T, base string) {
+	t.Helper()
+	probe := filepath.Join(base, "symlink-probe")
+	if err := os.Symlink("target", probe); err != nil {
+		t.Skipf("平台無法建立 symlink：%v", err)
+	}
+	if err := os.Remove(probe); err != nil {
+		t.Fatalf("移除 symlink probe 失敗：%v", err)
+	}
+}
+
+func assertCurrentLink(t *testing.T, base, link, want string) {
+	t.Helper()
+	target, err := os.Readlink(filepath.Join(base, link))
+	if err != nil {
+		t.Fatalf("讀取 symlink %s 失敗：%v", link, err)
+	}
+	if target != want {
+		t.Fatalf("symlink %s 指向 %q，預期 %q", link, target, want)
+	}
+}
@@ -0,0 +1,146 @@
+package zlogger
+
+import (
+	"cmp"
+	"errors"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// retainedLogFile 描述一個符合分級檔名格式的日誌檔。
+type retainedLogFile struct {
+	leaf   string
+	level  string
+	date   time.Time
+	backup int
+}
+
+// parseBackupIndex 只接受 numberedBackupLeaf 產生的正整數格式。
+func parseBackupIndex(value string) (int, bool) {
+	index, err := strconv.Atoi(value)
+	if err != nil || index <= 0 || strconv.Itoa(index) != value {
+		return 0, false
+	}
+	return index, true
+}
+
+// compareRetainedNewestFirst 依日期由新到舊排序；同日期的原檔最新，其次為較大的備份編號。
+func compareRetainedNewestFirst(a, b retainedLogFile) int {
+	if order := b.date.Compare(a.date); order != 0 {
+		return order
+	}
+	if a.backup == 0 || b.backup == 0 {
+		return cmp.Compare(a.backup, b.backup)
+	}
+	return cmp.Compare(b.backup, a.backup)
+}
+
+// selectExpiredLogFiles 依 maxAge 與 maxFiles 選出應刪除的檔案，active 中的檔案永不刪除。
+//
+// 檔案年齡以所屬週期結束時間 periodEnd(date) 起算。
+func selectExpiredLogFiles(
+	files []retainedLogFile,
+	active map[string]struct{},
+	now time.Time,
+	periodEnd func(time.Time) time.Time,
+	maxAge time.Duration,
+	maxFiles int,
+) []string {
+	byLevel := make(map[string][]retainedLogFile)
+	for _, file := range files {
+		byLevel[file.level] = append(byLevel[file.level], file)
+	}
+
+	var expired []string
+	for _, level := range slices.Sorted(maps.Keys(byLevel)) {
+		levelFiles := byLevel[level]
+		slices.SortFunc(levelFiles, compareRetainedNewestFirst)
+		for index, file := range levelFiles {
+			if _, ok := active[file.leaf]; ok {
+				continue
+			}
+			tooMany := maxFiles > 0 && index >= maxFiles
+			tooOld := maxAge > 0 && now.Sub(periodEnd(file.date)) > maxAge
+			if tooMany || tooOld {
+				expired = append(expired, file.leaf)
+			}
+		}
+	}
+	return expired
+}
+
+// pruneLogFiles 依保留政策刪除本 SplitOutput 建立的過期檔案。
+func (s *SplitOutput) pruneLogFiles() error {
+	return s.pruneRootedLogFiles(false)
+}
+
+// pruneStartupLogFiles 除保留政策外，另刪除先前程序於壓縮途中結束而留下的 .gz.tmp 暫存檔。
+//
+// 只在建立 SplitOutput、背景壓縮尚未開始前呼叫，避免刪除進行中的暫存檔。
+func (s *SplitOutput) pruneStartupLogFiles() error {
+	return s.pruneRootedLogFiles(true)
+}
+
+func (s *SplitOutput) pruneRootedLogFiles(removeTemporaries bool) error {
+	maxAge := s.settings.maxAge
+	maxFiles := s.settings.maxFiles
+	schedule := s.settings.schedule
+	if maxAge <= 0 && maxFiles <= 0 && !removeTemporaries {
+		return nil
+	}
+
+	s.mutex.Lock()
+	if s.closed {
+		s.mutex.Unlock()
+		return nil
+	}
+	leaves := s.leaves
+	s.mutex.Unlock()
+
+	now := s.now()
+	active := make(map[string]struct{}, len(leaves))
+	for _, leaf := range leaves {
+		active[leaf] = struct{}{}
+	}
+	values, err := s.fileName.values(s.filePrefix)
+	if err != nil {
+		return err
+	}
+	matcher, err := s.fileName.matcher(values, splitRouteNames(s.routes), schedule, s.dateIndex, now.Location())
+	if err != nil {
+		return err
+	}
+
+	return withLogRoot(s.directory, func(root *os.Root) error {
+		names, err := listRootedRegularFiles(root, s.directory)
+		if err != nil {
+			return err
+		}
+		files := make([]retainedLogFile, 0, len(names))
+		var removeErrs []error
+		for _, name := range names {
+			if stem, ok := strings.CutSuffix(name, compressingLogTmpSuffix); ok {
+				if _, matched := matcher.parse(stem); matched && removeTemporaries {
+					removeErrs = append(removeErrs, removeRootedLogFile(root, s.directory, name))
+				}
+				continue
+			}
+			file, ok := matcher.parse(name)
+			if ok {
+				files = append(files, file)
+			}
+		}
+		if maxAge <= 0 && maxFiles <= 0 {
+			return errors.Join(removeErrs...)
+		}
+
+		for _, leaf := range selectExpiredLogFiles(files, active, now, schedule.next, maxAge, maxFiles) {
+			removeErrs = append(removeErrs, removeRootedLogFile(root, s.directory, leaf))
+		}
+		return errors.Join(removeErrs...)
+	})
+}
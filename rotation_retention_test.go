@@ -0,0 +1,248 @@
+package zlogger
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseSplitLogLeaf(t *testing.T) {
+	tests := []struct {
+		leaf       string
+		wantOK     bool
+		wantLevel  string
+		wantDate   string
+		wantBackup int
+	}{
+		{leaf: "app-info-2026-07-29.log", wantOK: true, wantLevel: "info", wantDate: "2026-07-29"},
+		{leaf: "app-warn-2026-07-29-3.log", wantOK: true, wantLevel: "warn", wantDate: "2026-07-29", wantBackup: 3},
+		{leaf: "app-error-2026-07-29-12.log", wantOK: true, wantLevel: "error", wantDate: "2026-07-29", wantBackup: 12},
+		{leaf: "app-info-2026-07-29-0.log"},
+		{leaf: "app-info-2026-07-29-01.log"},
+		{leaf: "app-info-2026-07-29-x.log"},
+		{leaf: "app-info-2026-13-29.log"},
+		{leaf: "app-debug-2026-07-29.log"},
+		{leaf: "other-info-2026-07-29.log"},
+		{leaf: "app-extra-info-2026-07-29.log"},
+		{leaf: "app-info-2026-07-29.txt"},
+		{leaf: "app-info.log"},
+	}
+
+	template, err := parseFileNameTemplate(defaultSplitFileNameTemplate)
+	if err != nil {
+		t.Fatalf("解析預設樣板失敗：%v", err)
+	}
+	dateIndex, err := template.validateSplit(DailyRotation(), 3)
+	if err != nil {
+		t.Fatalf("驗證預設樣板失敗：%v", err)
+	}
+	matcher, err := template.matcher(
+		fileNameValues{prefix: "app"},
+		splitRouteNames(DefaultSplitRoutes()),
+		DailyRotation(),
+		dateIndex,
+		time.UTC,
+	)
+	if err != nil {
+		t.Fatalf("建立解析器失敗：%v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.leaf, func(t *testing.T) {
+			file, ok := matcher.parse(tt.leaf)
+			if ok != tt.wantOK {
+				t.Fatalf("解析結果 = %v，預期 %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if file.level != tt.wantLevel || file.backup != tt.wantBackup {
+				t.Fatalf("level/backup = %s/%d，預期 %s/%d", file.level, file.backup, tt.wantLevel, tt.wantBackup)
+			}
+			if got := file.date.Format(dailyRotationLayout); got != tt.wantDate {
+				t.Fatalf("日期 = %s，預期 %s", got, tt.wantDate)
+			}
+		})
+	}
+}
+
+func TestWithRetentionRejectsNonPositive(t *testing.T) {
+	for name, option := range map[string]FileOutputOption{
+		"MaxAge 為 0":   WithMaxAge(0),
+		"MaxAge 為負數":   WithMaxAge(-time.Hour),
+		"MaxFiles 為 0": WithMaxFiles(0),
+		"MaxFiles 為負數": WithMaxFiles(-1),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := resolveFileOutputOptions(option); !errors.Is(err, ErrInvalidRotation) {
+				t.Fatalf("錯誤 = %v，預期 ErrInvalidRotation", err)
+			}
+		})
+	}
+}
+
+func TestSplitOutputMaxFilesPrunesOldestAtStartup(t *testing.T) {
+	base := t.TempDir()
+	now := time.Date(2026, time.July, 29, 10, 0, 0, 0, time.Local)
+	createRetentionFixtures(t, base,
+		"app-info-2026-07-26.log",
+		"app-info-2026-07-27.log",
+		"app-info-2026-07-28-1.log",
+		"app-info-2026-07-28.log",
+		"app-warn-2026-07-27.log",
+	)
+
+	output := newRetentionTestOutput(t, base, now, WithMaxFiles(3))
+	if err := output.Close(); err != nil {
+		t.Fatalf("關閉 SplitOutput 失敗：%v", err)
+	}
+
+	assertRetainedLogFiles(t, base,
+		"app-error-2026-07-29.log",
+		"app-info-2026-07-28-1.log",
+		"app-info-2026-07-28.log",
+		"app-info-2026-07-29.log",
+		"app-warn-2026-07-27.log",
+		"app-warn-2026-07-29.log",
+	)
+}
+
+func TestSplitOutputMaxAgePrunesAfterRotation(t *testing.T) {
+	base := t.TempDir()
+	now := time.Date(2026, time.July, 29, 10, 0, 0, 0, time.Local)
+	createRetentionFixtures(t, base,
+		"app-info-2026-07-27.log",
+		"app-error-2026-07-28-1.log",
+	)
+
+	clock := newManualRotationClock(now)
+	settings, err := resolveFileOutputOptions(WithMaxAge(36 * time.Hour))
+	if err != nil {
+		t.Fatalf("解析 MaxAge option 失敗：%v", err)
+	}
+	output, err := newSplitOutputWithSettings(base, "app", clock, openSplitFilesWithPermissions, settings)
+	if err != nil {
+		t.Fatalf("建立 SplitOutput 失敗：%v", err)
+	}
+	t.Cleanup(func() { _ = output.Close() })
+
+	// 07-27 的週期在 07-28 00:00 結束，啟動時只過了 34 小時。
+	assertRetainedLogFiles(t, base,
+		"app-error-2026-07-28-1.log",
+		"app-error-2026-07-29.log",
+		"app-info-2026-07-27.log",
+		"app-info-2026-07-29.log",
+		"app-warn-2026-07-29.log",
+	)
+
+	timer := clock.nextTimer(t)
+	nextDay := time.Date(2026, time.July, 30, 0, 0, 0, 0, time.Local)
+	clock.setNow(nextDay)
+	timer.fire(nextDay)
+	clock.nextTimer(t)
+
+	assertRetainedLogFiles(t, base,
+		"app-error-2026-07-28-1.log",
+		"app-error-2026-07-29.log",
+		"app-error-2026-07-30.log",
+		"app-info-2026-07-29.log",
+		"app-info-2026-07-30.log",
+		"app-warn-2026-07-29.log",
+		"app-warn-2026-07-30.log",
+	)
+}
+
+func TestSplitOutputRetentionIgnoresForeignFiles(t *testing.T) {
+	parent := t.TempDir()
+	base := filepath.Join(parent, "logs")
+	if err := os.Mkdir(base, 0o700); err != nil {
+		t.Fatalf("建立 base 失敗：%v", err)
+	}
+	createRetentionFixtures(t, base,
+		"other-info-2020-01-01.log",
+		"app-info-2020-01-01.txt",
+		"app-debug-2020-01-01.log",
+		"notes.log",
+	)
+	outside := filepath.Join(parent, "outside.log")
+	if err := os.WriteFile(outside, []byte("外部\n"), 0o600); err != nil {
+		t.Fatalf("建立外部檔案失敗：%v", err)
+	}
+	link := "app-info-2020-01-02.log"
+	symlinkCreated := os.Symlink(outside, filepath.Join(base, link)) == nil
+
+	now := time.Date(2026, time.July, 29, 10, 0, 0, 0, time.Local)
+	output := newRetentionTestOutput(t, base, now, WithMaxAge(time.Hour), WithMaxFiles(1))
+	if err := output.Close(); err != nil {
+		t.Fatalf("關閉 SplitOutput 失敗：%v", err)
+	}
+
+	want := []string{
+		"app-debug-2020-01-01.log",
+		"app-error-2026-07-29.log",
+		"app-info-2020-01-01.txt",
+		"app-info-2026-07-29.log",
+		"app-warn-2026-07-29.log",
+		"notes.log",
+		"other-info-2020-01-01.log",
+	}
+	if symlinkCreated {
+		want = append(want, link)
+	}
+	assertRetainedLogFiles(t, base, want...)
+	assertFileContent(t, outside, "外部\n")
+}
+
+func newRetentionTestOutput(
+	t *testing.T,
+	base string,
+	now time.Time,
+	opts ...FileOutputOption,
+) *SplitOutput {
+	t.Helper()
+	settings, err := resolveFileOutputOptions(opts...)
+	if err != nil {
+		t.Fatalf("解析保留 options 失敗：%v", err)
+	}
+	output, err := newSplitOutputWithSettings(
+		base,
+		"app",
+		newManualRotationClock(now),
+		openSplitFilesWithPermissions,
+		settings,
+	)
+	if err != nil {
+		t.Fatalf("建立 SplitOutput 失敗：%v", err)
+	}
+	return output
+}
+
+func createRetentionFixtures(t *testing.T, base string, leaves ...string) {
+	t.Helper()
+	for _, leaf := range leaves {
+		if err := os.WriteFile(filepath.Join(base, leaf), []byte(leaf+"\n"), 0o600); err != nil {
+			t.Fatalf("建立測試檔案 %q 失敗：%v", leaf, err)
+		}
+	}
+}
+
+func assertRetainedLogFiles(t *testing.T, base string, want ...string) {
+	t.Helper()
+	entries, err := os.ReadDir(base)
+	if err != nil {
+		t.Fatalf("讀取日誌目錄失敗：%v", err)
+	}
+	got := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		got = append(got, entry.Name())
+	}
+	slices.Sort(got)
+	want = slices.Clone(want)
+	slices.Sort(want)
+	if !slices.Equal(got, want) {
+		t.Fatalf("保留檔案 = %v，預期 %v", got, want)
+	}
+}
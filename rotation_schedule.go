@@ -0,0 +1,181 @@
+package zlogger
+
+import (
+	"fmt"
+	"time"
+)
+
+type rotationPeriod uint8
+
+const (
+	rotationPeriodDaily rotationPeriod = iota
+	rotationPeriodHourly
+	rotationPeriodWeekly
+	rotationPeriodEvery
+)
+
+const (
+	dailyRotationLayout  = "2006-01-02"
+	hourlyRotationLayout = "2006-01-02T15"
+)
+
+// RotationSchedule 描述換檔週期，以及檔名中代表週期起點的時間格式。
+//
+// 零值等同 DailyRotation。
+type RotationSchedule struct {
+	period   rotationPeriod
+	interval time.Duration
+	layout   string
+}
+
+// DailyRotation 在每日 00:00 換檔，檔名日期格式為 2006-01-02。
+func DailyRotation() RotationSchedule {
+	return RotationSchedule{period: rotationPeriodDaily, layout: dailyRotationLayout}
+}
+
+// HourlyRotation 在每小時整點換檔，檔名日期格式為 2006-01-02T15。
+func HourlyRotation() RotationSchedule {
+	return RotationSchedule{period: rotationPeriodHourly, layout: hourlyRotationLayout}
+}
+
+// WeeklyRotation 在每週一 00:00 換檔，檔名日期為該週週一的 2006-01-02。
+func WeeklyRotation() RotationSchedule {
+	return RotationSchedule{period: rotationPeriodWeekly, layout: dailyRotationLayout}
+}
+
+// RotationEvery 以自訂週期換檔，interval 需介於 1s 與 24h。
+//
+// 週期起點由 WithRotationLocation 時區的當日 00:00 起算，每經過 interval 為一個週期；
+// 24h 不是 interval 的整數倍時，當日最後一個週期於隔日 00:00 提早結束。例如在 Asia/Taipei
+// 每 6 小時換檔的邊界為當地 00:00、06:00、12:00、18:00。夏令時間切換日以實際經過時間計算，
+// 當日其後的邊界會偏移一小時。
+//
+// layout 必須能完整表示週期起點，且格式化後為安全的 leaf name 片段，
+// 例如每 15 分鐘可使用 2006-01-02T1504。
+func RotationEvery(interval time.Duration, layout string) RotationSchedule {
+	return RotationSchedule{period: rotationPeriodEvery, interval: interval, layout: layout}
+}
+
+// WithRotationLocation 固定 SplitOutput 計算換檔邊界與檔名日期所用的時區。
+//
+// 未設定時沿用系統時鐘回傳的時區（通常為 time.Local）。多台主機需要在同一瞬間
+// 換檔，或每小時換檔需避開夏令時間結束時重複的整點時，建議使用 time.UTC。
+func WithRotationLocation(location *time.Location) FileOutputOption {
+	return fileOutputOptionFunc(func(settings *fileOutputSettings) error {
+		if location == nil {
+			return fmt.Errorf("%w: 換檔時區不可為 nil", ErrInvalidRotation)
+		}
+		settings.location = location
+		return nil
+	})
+}
+
+// WithRotationSchedule 設定 SplitOutput 的換檔週期與檔名日期格式。
+//
+// 未設定時使用 DailyRotation。週期邊界依 WithRotationLocation 指定的時區計算。
+func WithRotationSchedule(schedule RotationSchedule) FileOutputOption {
+	return fileOutputOptionFunc(func(settings *fileOutputSettings) error {
+		schedule = schedule.normalized()
+		if err := schedule.validate(); err != nil {
+			return err
+		}
+		settings.schedule = schedule
+		return nil
+	})
+}
+
+func (r RotationSchedule) normalized() RotationSchedule {
+	if r == (RotationSchedule{}) {
+		return DailyRotation()
+	}
+	return r
+}
+
+func (r RotationSchedule) validate() error {
+	switch r.period {
+	case rotationPeriodDaily, rotationPeriodHourly, rotationPeriodWeekly:
+	case rotationPeriodEvery:
+		if r.interval < time.Second {
+			return fmt.Errorf("%w: 換檔週期 %s 不可小於 1s", ErrInvalidRotation, r.interval)
+		}
+		if r.interval > 24*time.Hour {
+			return fmt.Errorf("%w: 換檔週期 %s 不可大於 24h，請改用 DailyRotation 或 WeeklyRotation", ErrInvalidRotation, r.interval)
+		}
+	default:
+		return fmt.Errorf("%w: 未知換檔週期 %d", ErrInvalidRotation, r.period)
+	}
+	return r.validateLayout(r.layout)
+}
+
+// validateLayout 確認 layout 能作為本週期的檔名日期。
+func (r RotationSchedule) validateLayout(layout string) error {
+	if layout == "" {
+		return fmt.Errorf("%w: 換檔日期格式不可為空", ErrInvalidRotation)
+	}
+
+	// 以固定時間驗證格式化結果可作為 leaf name 片段，並能解析回相同的週期起點。
+	sample := r.start(time.Date(2026, time.January, 7, 13, 47, 29, 0, time.UTC))
+	token := sample.Format(layout)
+	if err := validateLogLeaf(token, false); err != nil {
+		return fmt.Errorf("%w: 換檔日期格式 %q: %w", ErrInvalidRotation, layout, err)
+	}
+	parsed, err := time.ParseInLocation(layout, token, time.UTC)
+	if err != nil || !parsed.Equal(sample) {
+		return fmt.Errorf("%w: 換檔日期格式 %q 無法完整表示週期起點", ErrInvalidRotation, layout)
+	}
+	if r.start(r.next(sample)).Format(layout) == token {
+		return fmt.Errorf("%w: 換檔日期格式 %q 無法區分相鄰週期", ErrInvalidRotation, layout)
+	}
+	return nil
+}
+
+// start 回傳 t 所屬週期的起點。
+func (r RotationSchedule) start(t time.Time) time.Time {
+	switch r.period {
+	case rotationPeriodHourly:
+		return t.Add(-time.Duration(t.Minute())*time.Minute -
+			time.Duration(t.Second())*time.Second -
+			time.Duration(t.Nanosecond()))
+	case rotationPeriodWeekly:
+		return midnight(t, -daysSinceMonday(t))
+	case rotationPeriodEvery:
+		day := midnight(t, 0)
+		return day.Add(t.Sub(day).Truncate(r.interval))
+	default:
+		return midnight(t, 0)
+	}
+}
+
+// next 回傳 t 之後的下一個換檔時間點。
+//
+// 每日與每週以日曆日期計算，夏令時間切換日的 23 或 25 小時不會造成跳過或重複換檔。
+func (r RotationSchedule) next(t time.Time) time.Time {
+	switch r.period {
+	case rotationPeriodHourly:
+		return r.start(t).Add(time.Hour)
+	case rotationPeriodWeekly:
+		return midnight(t, 7-daysSinceMonday(t))
+	case rotationPeriodEvery:
+		next := r.start(t).Add(r.interval)
+		if tomorrow := midnight(t, 1); next.After(tomorrow) {
+			return tomorrow
+		}
+		return next
+	default:
+		return midnight(t, 1)
+	}
+}
+
+// midnight 回傳 t 所在日曆日期加 days 天的 00:00；該時間不存在時由 time.Date 正規化。
+func midnight(t time.Time, days int) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day()+days, 0, 0, 0, 0, t.Location())
+}
+
+func daysSinceMonday(t time.Time) int {
+	return (int(t.Weekday()) + 6) % 7
+}
+
+// token 回傳 t 所屬週期在檔名中的日期字串。
+func (r RotationSchedule) token(t time.Time) string {
+	return r.start(t).Format(r.layout)
+}
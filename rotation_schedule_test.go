@@ -0,0 +1,333 @@
+package zlogger
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestRotationScheduleBoundaries(t *testing.T) {
+	// 2026-07-29 是週三。
+	now := time.Date(2026, time.July, 29, 10, 47, 12, 5, time.UTC)
+	tests := []struct {
+		name      string
+		schedule  RotationSchedule
+		wantStart time.Time
+		wantNext  time.Time
+		wantToken string
+	}{
+		{
+			name:      "daily",
+			schedule:  DailyRotation(),
+			wantStart: time.Date(2026, time.July, 29, 0, 0, 0, 0, time.UTC),
+			wantNext:  time.Date(2026, time.July, 30, 0, 0, 0, 0, time.UTC),
+			wantToken: "2026-07-29",
+		},
+		{
+			name:      "zero value",
+			schedule:  RotationSchedule{},
+			wantStart: time.Date(2026, time.July, 29, 0, 0, 0, 0, time.UTC),
+			wantNext:  time.Date(2026, time.July, 30, 0, 0, 0, 0, time.UTC),
+			wantToken: "2026-07-29",
+		},
+		{
+			name:      "hourly",
+			schedule:  HourlyRotation(),
+			wantStart: time.Date(2026, time.July, 29, 10, 0, 0, 0, time.UTC),
+			wantNext:  time.Date(2026, time.July, 29, 11, 0, 0, 0, time.UTC),
+			wantToken: "2026-07-29T10",
+		},
+		{
+			name:      "weekly",
+			schedule:  WeeklyRotation(),
+			wantStart: time.Date(2026, time.July, 27, 0, 0, 0, 0, time.UTC),
+			wantNext:  time.Date(2026, time.August, 3, 0, 0, 0, 0, time.UTC),
+			wantToken: "2026-07-27",
+		},
+		{
+			name:      "every 15 minutes",
+			schedule:  RotationEvery(15*time.Minute, "2006-01-02T1504"),
+			wantStart: time.Date(2026, time.July, 29, 10, 45, 0, 0, time.UTC),
+			wantNext:  time.Date(2026, time.July, 29, 11, 0, 0, 0, time.UTC),
+			wantToken: "2026-07-29T1045",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := tt.schedule.normalized()
+			if err := schedule.validate(); err != nil {
+				t.Fatalf("內建週期驗證失敗：%v", err)
+			}
+			if got := schedule.start(now); !got.Equal(tt.wantStart) {
+				t.Errorf("start = %s，預期 %s", got, tt.wantStart)
+			}
+			if got := schedule.next(now); !got.Equal(tt.wantNext) {
+				t.Errorf("next = %s，預期 %s", got, tt.wantNext)
+			}
+			if got := schedule.token(now); got != tt.wantToken {
+				t.Errorf("token = %q，預期 %q", got, tt.wantToken)
+			}
+		})
+	}
+}
+
+func TestWithRotationScheduleRejectsInvalidSchedules(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule RotationSchedule
+	}{
+		{name: "零週期", schedule: RotationEvery(0, "2006-01-02T150405")},
+		{name: "小於一秒", schedule: RotationEvery(time.Millisecond, "2006-01-02T150405")},
+		{name: "空格式", schedule: RotationEvery(time.Hour, "")},
+		{name: "含路徑分隔符", schedule: RotationEvery(24*time.Hour, "2006/01/02")},
+		{name: "無法表示週期起點", schedule: RotationEvery(6*time.Hour, "2006-01-02")},
+		{name: "無法區分相鄰週期", schedule: RotationEvery(time.Hour, "15")},
+		{name: "大於一天", schedule: RotationEvery(48*time.Hour, "2006-01-02")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := filepath.Join(t.TempDir(), "不應建立")
+			output, err := NewSplitOutputWithOptions(base, "app", WithRotationSchedule(tt.schedule))
+			if output != nil {
+				_ = output.Close()
+				t.Fatal("無效週期不應回傳 SplitOutput")
+			}
+			if !errors.Is(err, ErrInvalidRotation) {
+				t.Fatalf("錯誤 = %v，預期 ErrInvalidRotation", err)
+			}
+			assertPathDoesNotExist(t, base)
+		})
+	}
+}
+
+func TestSplitOutputHourlyRotation(t *testing.T) {
+	base := t.TempDir()
+	now := time.Date(2026, time.July, 29, 10, 20, 0, 0, time.Local)
+	clock := newManualRotationClock(now)
+	settings, err := resolveFileOutputOptions(
+		WithRotationSchedule(HourlyRotation()),
+		WithMaxFiles(1),
+	)
+	if err != nil {
+		t.Fatalf("解析週期 option 失敗：%v", err)
+	}
+	output, err := newSplitOutputWithSettings(base, "app", clock, openSplitFilesWithPermissions, settings)
+	if err != nil {
+		t.Fatalf("建立 SplitOutput 失敗：%v", err)
+	}
+	t.Cleanup(func() { _ = output.Close() })
+
+	timer := clock.nextTimer(t)
+	if durations := clock.timerDurations(); durations[0] != 40*time.Minute {
+		t.Fatalf("第一次換檔等待 %s，預期 40m", durations[0])
+	}
+	nextHour := time.Date(2026, time.July, 29, 11, 0, 0, 0, time.Local)
+	clock.setNow(nextHour)
+	timer.fire(nextHour)
+	clock.nextTimer(t)
+	if durations := clock.timerDurations(); durations[1] != time.Hour {
+		t.Fatalf("整點換檔後等待 %s，預期 1h", durations[1])
+	}
+
+	if _, err := output.Write(zapcore.WarnLevel, []byte("hourly\n")); err != nil {
+		t.Fatalf("寫入 warn 失敗：%v", err)
+	}
+	if err := output.Sync(); err != nil {
+		t.Fatalf("同步 SplitOutput 失敗：%v", err)
+	}
+	assertFileContent(t, filepath.Join(base, "app-warn-2026-07-29T11.log"), "hourly\n")
+	assertRetainedLogFiles(t, base,
+		"app-error-2026-07-29T11.log",
+		"app-info-2026-07-29T11.log",
+		"app-warn-2026-07-29T11.log",
+	)
+}
+
+func TestRotationScheduleAcrossDSTTransitions(t *testing.T) {
+	location, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("載入時區失敗：%v", err)
+	}
+	tests := []struct {
+		name     string
+		schedule RotationSchedule
+		now      time.Time
+		wantNext time.Time
+		wantWait time.Duration
+	}{
+		{
+			name:     "daily spring forward",
+			schedule: DailyRotation(),
+			now:      time.Date(2026, time.March, 8, 0, 30, 0, 0, location),
+			wantNext: time.Date(2026, time.March, 9, 0, 0, 0, 0, location),
+			wantWait: 22*time.Hour + 30*time.Minute,
+		},
+		{
+			name:     "daily fall back",
+			schedule: DailyRotation(),
+			now:      time.Date(2026, time.November, 1, 0, 30, 0, 0, location),
+			wantNext: time.Date(2026, time.November, 2, 0, 0, 0, 0, location),
+			wantWait: 24*time.Hour + 30*time.Minute,
+		},
+		{
+			name:     "daily late on fall back day",
+			schedule: DailyRotation(),
+			now:      time.Date(2026, time.November, 1, 23, 30, 0, 0, location),
+			wantNext: time.Date(2026, time.November, 2, 0, 0, 0, 0, location),
+			wantWait: 30 * time.Minute,
+		},
+		{
+			name:     "weekly spanning spring forward",
+			schedule: WeeklyRotation(),
+			now:      time.Date(2026, time.March, 2, 0, 0, 0, 0, location),
+			wantNext: time.Date(2026, time.March, 9, 0, 0, 0, 0, location),
+			wantWait: 7*24*time.Hour - time.Hour,
+		},
+		{
+			name:     "hourly during repeated hour",
+			schedule: HourlyRotation(),
+			now:      time.Date(2026, time.November, 1, 5, 30, 0, 0, time.UTC).In(location),
+			wantNext: time.Date(2026, time.November, 1, 6, 0, 0, 0, time.UTC),
+			wantWait: 30 * time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := tt.schedule.next(tt.now)
+			if !next.Equal(tt.wantNext) {
+				t.Fatalf("next = %s，預期 %s", next, tt.wantNext)
+			}
+			if wait := next.Sub(tt.now); wait != tt.wantWait {
+				t.Fatalf("等待 %s，預期 %s", wait, tt.wantWait)
+			}
+			if tt.schedule.period == rotationPeriodHourly {
+				// 重複的 01 時在本地時區格式相同，需固定 UTC 才能分開檔案。
+				return
+			}
+			if tt.schedule.token(next) == tt.schedule.token(tt.now) {
+				t.Fatalf("邊界前後 token 都是 %q，會重複寫入同一檔案", tt.schedule.token(next))
+			}
+		})
+	}
+}
+
+func TestRotationEveryAlignsToLocalMidnight(t *testing.T) {
+	location, err := time.LoadLocation("Asia/Taipei")
+	if err != nil {
+		t.Fatalf("載入時區失敗：%v", err)
+	}
+	tests := []struct {
+		name      string
+		interval  time.Duration
+		now       time.Time
+		wantStart time.Time
+		wantNext  time.Time
+	}{
+		{
+			name:      "every 6 hours",
+			interval:  6 * time.Hour,
+			now:       time.Date(2026, time.July, 29, 7, 30, 0, 0, location),
+			wantStart: time.Date(2026, time.July, 29, 6, 0, 0, 0, location),
+			wantNext:  time.Date(2026, time.July, 29, 12, 0, 0, 0, location),
+		},
+		{
+			name:      "before first boundary",
+			interval:  6 * time.Hour,
+			now:       time.Date(2026, time.July, 29, 1, 0, 0, 0, location),
+			wantStart: time.Date(2026, time.July, 29, 0, 0, 0, 0, location),
+			wantNext:  time.Date(2026, time.July, 29, 6, 0, 0, 0, location),
+		},
+		{
+			name:      "last period ends at midnight",
+			interval:  7 * time.Hour,
+			now:       time.Date(2026, time.July, 29, 22, 0, 0, 0, location),
+			wantStart: time.Date(2026, time.July, 29, 21, 0, 0, 0, location),
+			wantNext:  time.Date(2026, time.July, 30, 0, 0, 0, 0, location),
+		},
+		{
+			name:      "every 24 hours",
+			interval:  24 * time.Hour,
+			now:       time.Date(2026, time.July, 29, 3, 0, 0, 0, location),
+			wantStart: time.Date(2026, time.July, 29, 0, 0, 0, 0, location),
+			wantNext:  time.Date(2026, time.July, 30, 0, 0, 0, 0, location),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := RotationEvery(tt.interval, "2006-01-02T15")
+			if err := schedule.validate(); err != nil {
+				t.Fatalf("驗證失敗：%v", err)
+			}
+			if got := schedule.start(tt.now); !got.Equal(tt.wantStart) {
+				t.Errorf("start = %s，預期 %s", got, tt.wantStart)
+			}
+			if got := schedule.next(tt.now); !got.Equal(tt.wantNext) {
+				t.Errorf("next = %s，預期 %s", got, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestWithRotationLocationRejectsNil(t *testing.T) {
+	base := filepath.Join(t.TempDir(), "不應建立")
+	output, err := NewSplitOutputWithOptions(base, "app", WithRotationLocation(nil))
+	if output != nil {
+		_ = output.Close()
+		t.Fatal("nil 時區不應回傳 SplitOutput")
+	}
+	if !errors.Is(err, ErrInvalidRotation) {
+		t.Fatalf("錯誤 = %v，預期 ErrInvalidRotation", err)
+	}
+	assertPathDoesNotExist(t, base)
+}
+
+func TestSplitOutputRotationLocationPinsDateAndBoundary(t *testing.T) {
+	base := t.TempDir()
+	taipei := time.FixedZone("UTC+8", 8*60*60)
+	// 台北時間 07-29 05:00 即 UTC 07-28 21:00。
+	now := time.Date(2026, time.July, 29, 5, 0, 0, 0, taipei)
+	clock := newManualRotationClock(now)
+	settings, err := resolveFileOutputOptions(WithRotationLocation(time.UTC))
+	if err != nil {
+		t.Fatalf("解析時區 option 失敗：%v", err)
+	}
+	output, err := newSplitOutputWithSettings(base, "app", clock, openSplitFilesWithPermissions, settings)
+	if err != nil {
+		t.Fatalf("建立 SplitOutput 失敗：%v", err)
+	}
+	t.Cleanup(func() { _ = output.Close() })
+
+	timer := clock.nextTimer(t)
+	if durations := clock.timerDurations(); durations[0] != 3*time.Hour {
+		t.Fatalf("第一次換檔等待 %s，預期到 UTC 午夜的 3h", durations[0])
+	}
+	assertRetainedLogFiles(t, base,
+		"app-error-2026-07-28.log",
+		"app-info-2026-07-28.log",
+		"app-warn-2026-07-28.log",
+	)
+
+	// 計時器略早於邊界觸發時，仍應換到新日期且不重複換檔。
+	early := now.Add(3*time.Hour - time.Millisecond)
+	clock.setNow(early)
+	timer.fire(early)
+	clock.nextTimer(t)
+	if durations := clock.timerDurations(); durations[1] != 24*time.Hour+time.Millisecond {
+		t.Fatalf("換檔後等待 %s，預期直到下一個 UTC 午夜", durations[1])
+	}
+	if _, err := output.Write(zapcore.InfoLevel, []byte("utc\n")); err != nil {
+		t.Fatalf("寫入 info 失敗：%v", err)
+	}
+	if err := output.Sync(); err != nil {
+		t.Fatalf("同步 SplitOutput 失敗：%v", err)
+	}
+	assertFileContent(t, filepath.Join(base, "app-info-2026-07-29.log"), "utc\n")
+}
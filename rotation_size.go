@@ -0,0 +1,121 @@
+package zlogger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// sizeRollingFile 在寫入超過大小上限前，將目前檔案改名為編號備份並重新開檔。
+//
+// 所有方法都必須在 SplitOutput 的 mutex 保護下呼叫；onRolled 與 onFailed 同樣在持有 mutex 時執行。
+type sizeRollingFile struct {
+	directory string
+	leaf      string
+	filePerm  os.FileMode
+	maxSize   int64
+	onRolled  func(backup string)
+	onFailed  func(err error)
+
+	file writeSyncCloser
+	size int64
+}
+
+type fileStater interface {
+	Stat() (os.FileInfo, error)
+}
+
+func newSizeRollingFile(
+	file writeSyncCloser,
+	directory string,
+	leaf string,
+	filePerm os.FileMode,
+	maxSize int64,
+	onRolled func(backup string),
+	onFailed func(err error),
+) *sizeRollingFile {
+	return &sizeRollingFile{
+		directory: directory,
+		leaf:      leaf,
+		filePerm:  filePerm,
+		maxSize:   maxSize,
+		onRolled:  onRolled,
+		onFailed:  onFailed,
+		file:      file,
+		size:      currentFileSize(file),
+	}
+}
+
+// currentFileSize 回傳既有檔案大小，讓同一天重新啟動時沿用已寫入的容量。
+func currentFileSize(file writeSyncCloser) int64 {
+	stater, ok := file.(fileStater)
+	if !ok {
+		return 0
+	}
+	info, err := stater.Stat()
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+func (f *sizeRollingFile) Write(data []byte) (int, error) {
+	if f.size > 0 && f.size+int64(len(data)) > f.maxSize {
+		if err := f.rollOver(); err != nil && f.onFailed != nil {
+			f.onFailed(err)
+		}
+	}
+
+	written, err := f.file.Write(data)
+	f.size += int64(written)
+	return written, err
+}
+
+// Stat 回傳目前 handle 的檔案資訊，供檔案識別檢查使用。
+func (f *sizeRollingFile) Stat() (os.FileInfo, error) {
+	stater, ok := f.file.(fileStater)
+	if !ok {
+		return nil, fmt.Errorf("日誌 %q 不支援 Stat: %w", f.leaf, os.ErrInvalid)
+	}
+	return stater.Stat()
+}
+
+func (f *sizeRollingFile) Sync() error {
+	return f.file.Sync()
+}
+
+func (f *sizeRollingFile) Close() error {
+	return f.file.Close()
+}
+
+// rollOver 先關閉目前檔案再改名，確保 Windows 也能移動檔案。
+//
+// 改名失敗時重新開啟原檔沿用，並將計數歸零，待再寫入 maxSize 後重試，
+// 避免每筆寫入都重複失敗。
+func (f *sizeRollingFile) rollOver() error {
+	closeErr := f.file.Close()
+	if closeErr != nil {
+		closeErr = fmt.Errorf("關閉待換檔日誌 %q: %w", f.leaf, closeErr)
+	}
+	backup, renameErr := rollRootedLogFile(f.directory, f.leaf)
+	if renameErr == nil && f.onRolled != nil {
+		f.onRolled(backup)
+	}
+
+	opened, openErr := openRootedLogFilesWithPermissions(f.directory, f.filePerm, f.leaf)
+	if openErr != nil {
+		return errors.Join(closeErr, renameErr, openErr)
+	}
+	if len(opened) != 1 {
+		return errors.Join(
+			closeErr,
+			renameErr,
+			fmt.Errorf("取得日誌檔案數量 %d，預期 1: %w", len(opened), os.ErrInvalid),
+			closeRootedLogFiles(opened),
+		)
+	}
+
+	f.file = opened[0]
+	f.size = 0
+	return errors.Join(closeErr, renameErr)
+}
@@ -0,0 +1,163 @@
+package zlogger
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestWithMaxSizeRejectsNonPositive(t *testing.T) {
+	for _, maxSize := range []int64{0, -1} {
+		base := filepath.Join(t.TempDir(), "不應建立")
+		output, err := NewSplitOutputWithOptions(base, "app", WithMaxSize(maxSize))
+		if output != nil {
+			_ = output.Close()
+			t.Fatal("無效 MaxSize 不應回傳 SplitOutput")
+		}
+		if !errors.Is(err, ErrInvalidRotation) {
+			t.Fatalf("MaxSize %d 錯誤 = %v，預期 ErrInvalidRotation", maxSize, err)
+		}
+		assertPathDoesNotExist(t, base)
+	}
+}
+
+func TestSplitOutputMaxSizeRollsToNumberedBackups(t *testing.T) {
+	base := t.TempDir()
+	now := time.Date(2026, time.July, 29, 10, 0, 0, 0, time.Local)
+	clock := newManualRotationClock(now)
+	settings, err := resolveFileOutputOptions(WithMaxSize(10))
+	if err != nil {
+		t.Fatalf("解析 MaxSize option 失敗：%v", err)
+	}
+	output, err := newSplitOutputWithSettings(base, "app", clock, openSplitFilesWithPermissions, settings)
+	if err != nil {
+		t.Fatalf("建立 SplitOutput 失敗：%v", err)
+	}
+	t.Cleanup(func() { _ = output.Close() })
+
+	for _, message := range []string{"first\n", "second\n", "third\n"} {
+		if _, err := output.Write(zapcore.InfoLevel, []byte(message)); err != nil {
+			t.Fatalf("寫入 %q 失敗：%v", message, err)
+		}
+	}
+	if _, err := output.Write(zapcore.WarnLevel, []byte("warn\n")); err != nil {
+		t.Fatalf("寫入 warn 失敗：%v", err)
+	}
+	if err := output.Sync(); err != nil {
+		t.Fatalf("同步 SplitOutput 失敗：%v", err)
+	}
+
+	date := now.Format("2006-01-02")
+	assertFileContent(t, filepath.Join(base, "app-info-"+date+"-1.log"), "first\n")
+	assertFileContent(t, filepath.Join(base, "app-info-"+date+"-2.log"), "second\n")
+	assertFileContent(t, filepath.Join(base, "app-info-"+date+".log"), "third\n")
+	assertFileContent(t, filepath.Join(base, "app-warn-"+date+".log"), "warn\n")
+	if _, err := os.Stat(filepath.Join(base, "app-warn-"+date+"-1.log")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("未超過上限的 warn 檔不應換檔，Stat 錯誤 = %v", err)
+	}
+}
+
+func TestSplitOutputMaxSizeCountsExistingContent(t *testing.T) {
+	base := t.TempDir()
+	now := time.Date(2026, time.July, 29, 10, 0, 0, 0, time.Local)
+	date := now.Format("2006-01-02")
+	infoPath := filepath.Join(base, "app-info-"+date+".log")
+	if err := os.WriteFile(infoPath, []byte("existing\n"), 0o600); err != nil {
+		t.Fatalf("建立既有 info 檔失敗：%v", err)
+	}
+	backupPath := filepath.Join(base, "app-info-"+date+"-1.log")
+	if err := os.WriteFile(backupPath, []byte("older\n"), 0o600); err != nil {
+		t.Fatalf("建立既有備份失敗：%v", err)
+	}
+
+	settings, err := resolveFileOutputOptions(WithMaxSize(12))
+	if err != nil {
+		t.Fatalf("解析 MaxSize option 失敗：%v", err)
+	}
+	clock := newManualRotationClock(now)
+	output, err := newSplitOutputWithSettings(base, "app", clock, openSplitFilesWithPermissions, settings)
+	if err != nil {
+		t.Fatalf("建立 SplitOutput 失敗：%v", err)
+	}
+	if _, err := output.Write(zapcore.InfoLevel, []byte("new\n")); err != nil {
+		t.Fatalf("寫入 info 失敗：%v", err)
+	}
+	if err := output.Close(); err != nil {
+		t.Fatalf("關閉 SplitOutput 失敗：%v", err)
+	}
+
+	assertFileContent(t, backupPath, "older\n")
+	assertFileContent(t, filepath.Join(base, "app-info-"+date+"-2.log"), "existing\n")
+	assertFileContent(t, infoPath, "new\n")
+}
+
+func TestSplitOutputMaxSizeRestartsCountAfterDailyRotation(t *testing.T) {
+	base := t.TempDir()
+	now := time.Date(2026, time.July, 29, 10, 0, 0, 0, time.Local)
+	clock := newManualRotationClock(now)
+	settings, err := resolveFileOutputOptions(WithMaxSize(8))
+	if err != nil {
+		t.Fatalf("解析 MaxSize option 失敗：%v", err)
+	}
+	output, err := newSplitOutputWithSettings(base, "app", clock, openSplitFilesWithPermissions, settings)
+	if err != nil {
+		t.Fatalf("建立 SplitOutput 失敗：%v", err)
+	}
+	t.Cleanup(func() { _ = output.Close() })
+
+	if _, err := output.Write(zapcore.InfoLevel, []byte("today\n")); err != nil {
+		t.Fatalf("寫入 info 失敗：%v", err)
+	}
+	timer := clock.nextTimer(t)
+	nextDay := now.AddDate(0, 0, 1)
+	clock.setNow(nextDay)
+	timer.fire(nextDay)
+	clock.nextTimer(t)
+
+	if _, err := output.Write(zapcore.InfoLevel, []byte("nextday\n")); err != nil {
+		t.Fatalf("換日後寫入 info 失敗：%v", err)
+	}
+	if err := output.Sync(); err != nil {
+		t.Fatalf("同步 SplitOutput 失敗：%v", err)
+	}
+
+	entries, err := os.ReadDir(base)
+	if err != nil {
+		t.Fatalf("讀取日誌目錄失敗：%v", err)
+	}
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), "-1.log") {
+			t.Fatalf("換日後的新檔不應沿用前一天的大小，出現備份 %q", entry.Name())
+		}
+	}
+	assertFileContent(t, filepath.Join(base, "app-info-"+nextDay.Format("2006-01-02")+".log"), "nextday\n")
+}
+
+func TestRollRootedLogFileRejectsSymlink(t *testing.T) {
+	parent := t.TempDir()
+	base := filepath.Join(parent, "logs")
+	if err := os.Mkdir(base, 0o700); err != nil {
+		t.Fatalf("建立 base 失敗：%v", err)
+	}
+	outside := filepath.Join(parent, "outside.log")
+	if err := os.WriteFile(outside, []byte("外部\n"), 0o600); err != nil {
+		t.Fatalf("建立外部檔案失敗：%v", err)
+	}
+	if err := os.Symlink(outside, filepath.Join(base, "app.log")); err != nil {
+		t.Skipf("平台無法建立 symlink：%v", err)
+	}
+
+	backup, err := rollRootedLogFile(base, "app.log")
+	if !errors.Is(err, ErrUnsafeLogPath) {
+		t.Fatalf("錯誤 = %v，預期 ErrUnsafeLogPath", err)
+	}
+	if backup != "" {
+		t.Fatalf("失敗時不應回傳備份名稱，實際為 %q", backup)
+	}
+	assertFileContent(t, outside, "外部\n")
+}
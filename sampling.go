@@ -0,0 +1,140 @@
+package zlogger
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// defaultSamplingTick 是 SamplingConfig.Tick 為空時的取樣週期。
+const defaultSamplingTick = time.Second
+
+// SamplingConfig 啟用 zap sampler，限制熱迴圈重複寫入相同訊息。
+//
+// 每個 Tick 內，同級別且同訊息的前 Initial 筆全部寫入，之後每 Thereafter 筆寫入一筆；
+// Thereafter 為 0 時丟棄其餘日誌。Tick 為 time.ParseDuration 格式，空字串表示一秒。
+// Levels 以級別名稱覆寫 Initial 與 Thereafter。被取樣丟棄的筆數可由 Instance.Stats 取得。
+type SamplingConfig struct {
+	Initial    int                      `json:"initial" yaml:"initial" toml:"initial" mapstructure:"initial"`
+	Thereafter int                      `json:"thereafter" yaml:"thereafter" toml:"thereafter" mapstructure:"thereafter"`
+	Tick       string                   `json:"tick,omitempty" yaml:"tick,omitempty" toml:"tick,omitempty" mapstructure:"tick"`
+	Levels     map[string]SamplingLevel `json:"levels,omitempty" yaml:"levels,omitempty" toml:"levels,omitempty" mapstructure:"levels"`
+}
+
+// SamplingLevel 覆寫單一級別的取樣數量。
+type SamplingLevel struct {
+	Initial    int `json:"initial" yaml:"initial" toml:"initial" mapstructure:"initial"`
+	Thereafter int `json:"thereafter" yaml:"thereafter" toml:"thereafter" mapstructure:"thereafter"`
+}
+
+// clone 回傳獨立副本，並將 Levels 的 key 正規化為小寫。
+func (s *SamplingConfig) clone() *SamplingConfig {
+	if s == nil {
+		return nil
+	}
+	cloned := *s
+	cloned.Levels = nil
+	if s.Levels != nil {
+		cloned.Levels = make(map[string]SamplingLevel, len(s.Levels))
+		for name, level := range s.Levels {
+			cloned.Levels[strings.ToLower(name)] = level
+		}
+	}
+	return &cloned
+}
+
+func (s *SamplingConfig) validate() error {
+	if err := validateSamplingCounts("Sampling", s.Initial, s.Thereafter); err != nil {
+		return err
+	}
+	if _, err := s.tick(); err != nil {
+		return err
+	}
+	for name, level := range s.Levels {
+		switch strings.ToLower(name) {
+		case "debug", "info", "warn", "error", "fatal":
+		default:
+			return fmt.Errorf("%w: Sampling.Levels 的級別 %q 不受支援", ErrInvalidConfig, name)
+		}
+		if err := validateSamplingCounts("Sampling.Levels["+name+"]", level.Initial, level.Thereafter); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateSamplingCounts(field string, initial, thereafter int) error {
+	if initial <= 0 {
+		return fmt.Errorf("%w: %s.Initial 必須大於 0", ErrInvalidConfig, field)
+	}
+	if thereafter < 0 {
+		return fmt.Errorf("%w: %s.Thereafter 不可為負數", ErrInvalidConfig, field)
+	}
+	return nil
+}
+
+// tick 解析取樣週期；空字串回傳預設一秒。
+func (s *SamplingConfig) tick() (time.Duration, error) {
+	if s.Tick == "" {
+		return defaultSamplingTick, nil
+	}
+	tick, err := time.ParseDuration(s.Tick)
+	if err != nil {
+		return 0, fmt.Errorf("%w: Sampling.Tick %q 無法解析：%w", ErrInvalidConfig, s.Tick, err)
+	}
+	if tick <= 0 {
+		return 0, fmt.Errorf("%w: Sampling.Tick 必須大於 0", ErrInvalidConfig)
+	}
+	return tick, nil
+}
+
+// newSampledCore 依已驗證的 sampling 包裝 core，並以 sampledOut 累計被丟棄的筆數。
+//
+// 未設定級別覆寫時使用單一 zap sampler；否則每個級別各自建立 sampler。
+func newSampledCore(core zapcore.Core, sampling *SamplingConfig, sampledOut *atomic.Uint64) zapcore.Core {
+	tick, _ := sampling.tick()
+	hook := zapcore.SamplerHook(func(_ zapcore.Entry, decision zapcore.SamplingDecision) {
+		if decision&zapcore.LogDropped != 0 {
+			sampledOut.Add(1)
+		}
+	})
+	if len(sampling.Levels) == 0 {
+		return zapcore.NewSamplerWithOptions(core, tick, sampling.Initial, sampling.Thereafter, hook)
+	}
+
+	sampled := &levelSampledCore{Core: core}
+	for level := zapcore.DebugLevel; level <= zapcore.FatalLevel; level++ {
+		counts := SamplingLevel{Initial: sampling.Initial, Thereafter: sampling.Thereafter}
+		if override, ok := sampling.Levels[level.String()]; ok {
+			counts = override
+		}
+		sampled.samplers[level-zapcore.DebugLevel] = zapcore.NewSamplerWithOptions(
+			core, tick, counts.Initial, counts.Thereafter, hook,
+		)
+	}
+	return sampled
+}
+
+// levelSampledCore 依 entry 級別交給對應的 sampler；未涵蓋的級別直接交給內層 core。
+type levelSampledCore struct {
+	zapcore.Core
+	samplers [zapcore.FatalLevel - zapcore.DebugLevel + 1]zapcore.Core
+}
+
+func (c *levelSampledCore) With(fields []zapcore.Field) zapcore.Core {
+	cloned := &levelSampledCore{Core: c.Core.With(fields)}
+	for index, sampler := range c.samplers {
+		cloned.samplers[index] = sampler.With(fields)
+	}
+	return cloned
+}
+
+func (c *levelSampledCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if entry.Level >= zapcore.DebugLevel && entry.Level <= zapcore.FatalLevel {
+		return c.samplers[entry.Level-zapcore.DebugLevel].Check(entry, checked)
+	}
+	return c.Core.Check(entry, checked)
+}
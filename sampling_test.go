@@ -0,0 +1,98 @@
+package zlogger
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigValidateRejectsInvalidSampling(t *testing.T) {
+	tests := []struct {
+		name     string
+		sampling SamplingConfig
+	}{
+		{name: "Initial 為 0", sampling: SamplingConfig{Initial: 0, Thereafter: 10}},
+		{name: "Thereafter 為負數", sampling: SamplingConfig{Initial: 10, Thereafter: -1}},
+		{name: "Tick 無法解析", sampling: SamplingConfig{Initial: 10, Tick: "每秒"}},
+		{name: "Tick 非正數", sampling: SamplingConfig{Initial: 10, Tick: "-1s"}},
+		{
+			name:     "未知級別",
+			sampling: SamplingConfig{Initial: 10, Levels: map[string]SamplingLevel{"trace": {Initial: 1}}},
+		},
+		{
+			name:     "級別覆寫 Initial 為 0",
+			sampling: SamplingConfig{Initial: 10, Levels: map[string]SamplingLevel{"warn": {Initial: 0}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Sampling = &tt.sampling
+			if err := cfg.Validate(); !errors.Is(err, ErrInvalidConfig) {
+				t.Fatalf("錯誤 = %v，預期 ErrInvalidConfig", err)
+			}
+		})
+	}
+}
+
+func TestConfigPatchResolveCopiesSampling(t *testing.T) {
+	sampling := &SamplingConfig{
+		Initial:    10,
+		Thereafter: 100,
+		Tick:       "500ms",
+		Levels:     map[string]SamplingLevel{"ERROR": {Initial: 50, Thereafter: 10}},
+	}
+	cfg, err := (&ConfigPatch{Sampling: sampling}).Resolve()
+	if err != nil {
+		t.Fatalf("Resolve 失敗：%v", err)
+	}
+	sampling.Initial = 1
+	sampling.Levels["ERROR"] = SamplingLevel{Initial: 1}
+
+	if cfg.Sampling == sampling || cfg.Sampling.Initial != 10 || cfg.Sampling.Tick != "500ms" {
+		t.Fatalf("Sampling = %+v，預期獨立副本", cfg.Sampling)
+	}
+	if level, ok := cfg.Sampling.Levels["error"]; !ok || level.Initial != 50 || len(cfg.Sampling.Levels) != 1 {
+		t.Fatalf("Levels = %+v，預期正規化為小寫且不受原 map 影響", cfg.Sampling.Levels)
+	}
+}
+
+func TestNewWithOptionsAppliesSampling(t *testing.T) {
+	base := t.TempDir()
+	cfg := fileOutputTestConfig(base, "app.log")
+	cfg.Sampling = &SamplingConfig{
+		Initial:    2,
+		Thereafter: 0,
+		Tick:       "1h",
+		Levels:     map[string]SamplingLevel{"error": {Initial: 5}},
+	}
+	instance, err := NewWithOptions(cfg)
+	if err != nil {
+		t.Fatalf("建立 Instance 失敗：%v", err)
+	}
+	for range 10 {
+		instance.Logger().Info("熱迴圈 info")
+		instance.Logger().Error("熱迴圈 error")
+	}
+	if err := instance.Close(); err != nil {
+		t.Fatalf("關閉 Instance 失敗：%v", err)
+	}
+
+	//nolint:gosec // 測試只讀取 t.TempDir 內的預期檔案。
+	content, err := os.ReadFile(filepath.Join(base, "app.log"))
+	if err != nil {
+		t.Fatalf("讀取日誌失敗：%v", err)
+	}
+	if count := strings.Count(string(content), "熱迴圈 info"); count != 2 {
+		t.Fatalf("info 寫入 %d 筆，預期 2", count)
+	}
+	if count := strings.Count(string(content), "熱迴圈 error"); count != 5 {
+		t.Fatalf("error 寫入 %d 筆，預期 5", count)
+	}
+	if stats := instance.Stats(); stats.SampledEntries != 13 {
+		t.Fatalf("SampledEntries = %d，預期 13", stats.SampledEntries)
+	}
+}
@@ -1,7 +1,8 @@
 // Package zlogger 提供依日誌級別分檔的輸出功能。
 //
-// DEBUG 與 INFO 寫入 info 檔，WARN 寫入 warn 檔，ERROR 以上寫入 error 檔。
-// 每日換檔 worker 會在 Close 回傳前停止，避免關閉後重新開啟檔案。
+// 預設 DEBUG 與 INFO 寫入 info 檔，WARN 寫入 warn 檔，ERROR 以上寫入 error 檔；
+// WithSplitRoutes 可改用任意數量的具名級別範圍。
+// 換檔 worker 預設每日換檔，並會在 Close 回傳前停止，避免關閉後重新開啟檔案。
 package zlogger
 
 import (
@@ -9,6 +10,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"sync"
 	"time"
 
@@ -56,63 +58,86 @@ func NewSplitCore(encoder zapcore.Encoder, sinks SplitSinks) (zapcore.Core, erro
 }
 
 func buildSplitCore(encoder zapcore.Encoder, sinks SplitSinks) zapcore.Core {
-	infoLevel := zap.LevelEnablerFunc(func(level zapcore.Level) bool {
-		return level == zapcore.DebugLevel || level == zapcore.InfoLevel
-	})
-	warnLevel := zap.LevelEnablerFunc(func(level zapcore.Level) bool {
-		return level == zapcore.WarnLevel
-	})
-	errorLevel := zap.LevelEnablerFunc(func(level zapcore.Level) bool {
-		return level >= zapcore.ErrorLevel
-	})
-
-	return zapcore.NewTee(
-		zapcore.NewCore(encoder.Clone(), sinks.Info, infoLevel),
-		zapcore.NewCore(encoder.Clone(), sinks.Warn, warnLevel),
-		zapcore.NewCore(encoder.Clone(), sinks.Error, errorLevel),
+	return buildRoutedCore(
+		encoder,
+		DefaultSplitRoutes(),
+		[]zapcore.WriteSyncer{sinks.Info, sinks.Warn, sinks.Error},
+		nil,
 	)
 }
 
-type splitFileSet struct {
-	info  writeSyncCloser
-	warn  writeSyncCloser
-	error writeSyncCloser
+// buildRoutedCore 為每個 route 複製 encoder，sinks 與 routes 依位置對應。
+//
+// minimum 不為 nil 時，級別必須同時被 route 與 minimum 啟用。
+func buildRoutedCore(
+	encoder zapcore.Encoder,
+	routes []SplitRoute,
+	sinks []zapcore.WriteSyncer,
+	minimum zapcore.LevelEnabler,
+) zapcore.Core {
+	cores := make([]zapcore.Core, len(routes))
+	for index, route := range routes {
+		enabler := zap.LevelEnablerFunc(route.enabled)
+		if minimum != nil {
+			enabler = func(level zapcore.Level) bool {
+				return route.enabled(level) && minimum.Enabled(level)
+			}
+		}
+		cores[index] = zapcore.NewCore(encoder.Clone(), sinks[index], enabler)
+	}
+	return zapcore.NewTee(cores...)
 }
 
+// splitFileSet 依 route 順序保存各分級檔案。
+type splitFileSet []writeSyncCloser
+
 func (f splitFileSet) close() error {
 	var closeErrs []error
-	if f.info != nil {
-		closeErrs = append(closeErrs, f.info.Close())
-	}
-	if f.warn != nil {
-		closeErrs = append(closeErrs, f.warn.Close())
-	}
-	if f.error != nil {
-		closeErrs = append(closeErrs, f.error.Close())
+	for _, file := range f {
+		if file != nil {
+			closeErrs = append(closeErrs, file.Close())
+		}
 	}
 	return errors.Join(closeErrs...)
 }
 
-func (f splitFileSet) sync() error {
-	var syncErrs []error
-	if f.info != nil {
-		syncErrs = append(syncErrs, f.info.Sync())
-	}
-	if f.warn != nil {
-		syncErrs = append(syncErrs, f.warn.Sync())
+// failures 依序對每個檔案執行 operate，並將失敗包成 kind 事件；leaves 依位置提供檔名。
+func (f splitFileSet) failures(
+	kind InternalEventKind,
+	directory string,
+	leaves []string,
+	operate func(writeSyncCloser) error,
+) []InternalEvent {
+	var events []InternalEvent
+	for index, file := range f {
+		if file == nil {
+			continue
+		}
+		if err := operate(file); err != nil {
+			path := directory
+			if index < len(leaves) {
+				path = filepath.Join(directory, leaves[index])
+			}
+			events = append(events, InternalEvent{Kind: kind, Path: path, Err: err})
+		}
 	}
-	if f.error != nil {
-		syncErrs = append(syncErrs, f.error.Sync())
+	return events
+}
+
+func internalEventsError(events []InternalEvent) error {
+	errs := make([]error, len(events))
+	for index, event := range events {
+		errs[index] = event.Err
 	}
-	return errors.Join(syncErrs...)
+	return errors.Join(errs...)
 }
 
-type splitFileOpener func(directory, filePrefix, date string) (splitFileSet, error)
+// splitFileOpener 依 route 順序開啟 leaves。
+type splitFileOpener func(directory string, leaves []string) (splitFileSet, error)
 
 type splitFilePermissionOpener func(
 	directory string,
-	filePrefix string,
-	date string,
+	leaves []string,
 	filePerm os.FileMode,
 ) (splitFileSet, error)
 
@@ -148,40 +173,67 @@ func (t *systemRotationTimer) Stop() bool {
 	return t.timer.Stop()
 }
 
+// runEvery 每隔 interval 執行 task，直到 stop 被關閉。
+func runEvery(clock rotationClock, interval time.Duration, stop <-chan struct{}, task func()) {
+	for {
+		timer := clock.NewTimer(interval)
+		select {
+		case <-stop:
+			timer.Stop()
+			return
+		case <-timer.C():
+			task()
+		}
+	}
+}
+
 // SplitOutput 將不同日誌級別寫入不同檔案。
 //
-// 級別對應如下：
+// 使用預設路由表與檔名樣板時，級別對應如下：
 //   - DEBUG、INFO：{prefix}-info-{date}.log
 //   - WARN：{prefix}-warn-{date}.log
 //   - ERROR、DPANIC、PANIC、FATAL：{prefix}-error-{date}.log
+//
+// WithSplitRoutes 可改為任意數量的具名檔案。
 type SplitOutput struct {
-	directory  string
-	filePrefix string
-	infoOut    writeSyncCloser
-	warnOut    writeSyncCloser
-	errorOut   writeSyncCloser
-
-	mutex     sync.Mutex
-	closed    bool
-	closeOnce sync.Once
-	closeErr  error
-	stop      chan struct{}
-	done      chan struct{}
-	clock     rotationClock
-	opener    splitFilePermissionOpener
-	settings  fileOutputSettings
-}
-
-// NewSplitOutput 建立分級日誌輸出，並啟動每日換檔 worker。
+	directory   string
+	filePrefix  string
+	fileName    *fileNameTemplate
+	currentLink *fileNameTemplate
+	dateIndex   int
+	routes      []SplitRoute
+	leaves      []string
+	outputs     splitFileSet
+
+	mutex      sync.Mutex
+	pending    []InternalEvent
+	closed     bool
+	closeOnce  sync.Once
+	closeErr   error
+	stop       chan struct{}
+	done       chan struct{}
+	watchDone  chan struct{}
+	flushDone  chan struct{}
+	diskGuard  *diskSpaceGuard
+	compressor *backgroundQueue
+	hooks      *backgroundQueue
+	clock      rotationClock
+	opener     splitFilePermissionOpener
+	settings   fileOutputSettings
+}
+
+// NewSplitOutput 建立分級日誌輸出，並啟動換檔 worker。
 // filePrefix 只能是單一 leaf name；不安全路徑會回傳 ErrUnsafeLogPath。
 func NewSplitOutput(directory, filePrefix string) (*SplitOutput, error) {
 	return NewSplitOutputWithOptions(directory, filePrefix)
 }
 
-// NewSplitOutputWithOptions 建立可設定新建目錄與檔案權限的分級輸出。
+// NewSplitOutputWithOptions 建立可設定檔案權限與換檔行為的分級輸出。
 //
-// 未提供 options 時與 NewSplitOutput 相同。解析後的權限會沿用至每日換檔；
-// 實際權限仍受 process umask 限縮，且不會改寫既有權限。
+// 未提供 options 時與 NewSplitOutput 相同。解析後的權限會沿用至每次換檔；
+// 實際權限仍受 process umask 限縮，且不會改寫既有權限。WithRotationSchedule
+// 可調整換檔週期與檔名日期，WithFileNameTemplate 可調整檔名，WithSplitRoutes 可調整
+// 分級檔案，WithMaxSize 可另外依大小將目前檔案轉為 -1、-2…編號備份。
 func NewSplitOutputWithOptions(
 	directory string,
 	filePrefix string,
@@ -212,13 +264,8 @@ func newSplitOutput(
 	}
 	var permissionOpener splitFilePermissionOpener
 	if opener != nil {
-		permissionOpener = func(
-			directory string,
-			filePrefix string,
-			date string,
-			_ os.FileMode,
-		) (splitFileSet, error) {
-			return opener(directory, filePrefix, date)
+		permissionOpener = func(directory string, leaves []string, _ os.FileMode) (splitFileSet, error) {
+			return opener(directory, leaves)
 		}
 	}
 	return newSplitOutputWithSettings(
@@ -246,83 +293,177 @@ func newSplitOutputWithSettings(
 	if err := validateFileOutputPermission("檔案", settings.filePerm, 0o600); err != nil {
 		return nil, err
 	}
+	routes := settings.splitRoutes()
+	fileName, err := settings.fileNameTemplate(defaultSplitFileNameTemplate)
+	if err != nil {
+		return nil, err
+	}
+	dateIndex, err := fileName.validateSplit(settings.schedule, len(routes))
+	if err != nil {
+		return nil, err
+	}
+	currentLink, err := settings.currentLinkTemplate(defaultSplitCurrentLinkTemplate)
+	if err != nil {
+		return nil, err
+	}
+	if currentLink != nil {
+		if err := currentLink.validateCurrentLink(len(routes)); err != nil {
+			return nil, err
+		}
+	}
 	if err := os.MkdirAll(directory, settings.dirPerm); err != nil {
 		return nil, fmt.Errorf("建立日誌目錄失敗：%w", err)
 	}
 
 	output := &SplitOutput{
-		directory:  directory,
-		filePrefix: filePrefix,
-		stop:       make(chan struct{}),
-		done:       make(chan struct{}),
-		clock:      clock,
-		opener:     opener,
-		settings:   settings,
+		directory:   directory,
+		filePrefix:  filePrefix,
+		fileName:    fileName,
+		currentLink: currentLink,
+		dateIndex:   dateIndex,
+		routes:      routes,
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+		clock:       clock,
+		opener:      opener,
+		settings:    settings,
+	}
+	output.diskGuard = newDiskSpaceGuard(directory, settings, output.now)
+	if settings.compress {
+		output.compressor = newBackgroundQueue(0)
+	}
+	if len(settings.rotationHooks) > 0 {
+		output.hooks = newBackgroundQueue(rotationHookQueueSize)
 	}
 	if err := output.openFiles(); err != nil {
+		err = errors.Join(err, output.replaceFiles(nil).close())
+		output.compressor.close()
+		output.hooks.close()
 		return nil, err
 	}
+	settings.reportInternalEvent(InternalEventRetention, directory, output.pruneStartupLogFiles())
 
-	go output.rotateDaily()
+	go output.rotateOnSchedule()
+	if settings.checkInterval > 0 {
+		output.watchDone = make(chan struct{})
+		go output.watchFiles()
+	}
+	if settings.bufferSize > 0 {
+		output.flushDone = make(chan struct{})
+		go output.flushOnSchedule()
+	}
 	return output, nil
 }
 
-func openSplitFiles(directory, filePrefix, date string) (splitFileSet, error) {
-	return openSplitFilesWithPermissions(
-		directory,
-		filePrefix,
-		date,
-		defaultLogFileMode,
-	)
+func openSplitFiles(directory string, leaves []string) (splitFileSet, error) {
+	return openSplitFilesWithPermissions(directory, leaves, defaultLogFileMode)
 }
 
 func openSplitFilesWithPermissions(
 	directory string,
-	filePrefix string,
-	date string,
+	leaves []string,
 	filePerm os.FileMode,
 ) (splitFileSet, error) {
-	opened, err := openRootedLogFilesWithPermissions(
-		directory,
-		filePerm,
-		filePrefix+"-info-"+date+".log",
-		filePrefix+"-warn-"+date+".log",
-		filePrefix+"-error-"+date+".log",
-	)
+	opened, err := openRootedLogFilesWithPermissions(directory, filePerm, leaves...)
 	if err != nil {
-		return splitFileSet{}, fmt.Errorf("開啟分級日誌檔失敗：%w", err)
+		return nil, fmt.Errorf("開啟分級日誌檔失敗：%w", err)
 	}
-	if len(opened) != 3 {
-		return splitFileSet{}, errors.Join(
-			fmt.Errorf("取得分級日誌檔案數量 %d，預期 3: %w", len(opened), os.ErrInvalid),
+	if len(opened) != len(leaves) {
+		return nil, errors.Join(
+			fmt.Errorf("取得分級日誌檔案數量 %d，預期 %d: %w", len(opened), len(leaves), os.ErrInvalid),
 			closeRootedLogFiles(opened),
 		)
 	}
 
-	return splitFileSet{
-		info:  opened[0],
-		warn:  opened[1],
-		error: opened[2],
-	}, nil
+	files := make(splitFileSet, len(opened))
+	for index, file := range opened {
+		files[index] = file
+	}
+	return files, nil
 }
 
-func (s *SplitOutput) currentFiles() splitFileSet {
-	return splitFileSet{
-		info:  s.infoOut,
-		warn:  s.warnOut,
-		error: s.errorOut,
+// splitLeaves 依檔名樣板 render 週期 period 的各 route leaf name，順序同 routes。
+func (s *SplitOutput) splitLeaves(period time.Time) ([]string, error) {
+	values, err := s.fileName.values(s.filePrefix)
+	if err != nil {
+		return nil, err
 	}
+	leaves := make([]string, len(s.routes))
+	for index, route := range s.routes {
+		values.level = route.Name
+		leaf, err := s.fileName.renderLeaf(values, period, s.settings.schedule.layout)
+		if err != nil {
+			return nil, err
+		}
+		leaves[index] = leaf
+	}
+	return leaves, nil
+}
+
+// withSizeLimit 為每個分級檔案套用大小上限；未設定上限時原樣回傳。
+func (s *SplitOutput) withSizeLimit(files splitFileSet, leaves []string) splitFileSet {
+	maxSize := s.settings.maxSize
+	if maxSize <= 0 {
+		return files
+	}
+	limited := make(splitFileSet, len(files))
+	for index, file := range files {
+		if file != nil {
+			path := filepath.Join(s.directory, leaves[index])
+			limited[index] = newSizeRollingFile(
+				file,
+				s.directory,
+				leaves[index],
+				s.settings.filePerm,
+				maxSize,
+				s.onSizeRolled,
+				func(err error) { s.deferInternalEvent(InternalEventRotation, path, err) },
+			)
+		}
+	}
+	return limited
 }
 
 func (s *SplitOutput) replaceFiles(files splitFileSet) splitFileSet {
-	previous := s.currentFiles()
-	s.infoOut = files.info
-	s.warnOut = files.warn
-	s.errorOut = files.error
+	previous := s.outputs
+	s.outputs = files
 	return previous
 }
 
+// outputFor 回傳接收 level 的目前檔案；未設定路由表時使用預設三路。
+func (s *SplitOutput) outputFor(level zapcore.Level) (writeSyncCloser, bool) {
+	routes := s.routes
+	if routes == nil {
+		routes = DefaultSplitRoutes()
+	}
+	index := splitRouteIndex(routes, level)
+	if index < 0 {
+		return nil, false
+	}
+	if index >= len(s.outputs) {
+		return nil, true
+	}
+	return s.outputs[index], true
+}
+
+// now 回傳換檔計算使用的目前時間；設定 WithRotationLocation 時轉換至該時區。
+func (s *SplitOutput) now() time.Time {
+	now := s.clock.Now()
+	if s.settings.location != nil {
+		now = now.In(s.settings.location)
+	}
+	return now
+}
+
 func (s *SplitOutput) openFiles() error {
+	if s.clock == nil {
+		return s.openFilesAt(time.Time{})
+	}
+	return s.openFilesAt(s.now())
+}
+
+// openFilesAt 開啟 at 所屬週期的檔案並替換目前檔案。
+func (s *SplitOutput) openFilesAt(at time.Time) error {
 	s.mutex.Lock()
 	if s.closed {
 		s.mutex.Unlock()
@@ -337,11 +478,15 @@ func (s *SplitOutput) openFiles() error {
 		return fmt.Errorf("分級輸出尚未初始化：%w", os.ErrInvalid)
 	}
 
-	date := clock.Now().Format("2006-01-02")
-	newFiles, err := opener(s.directory, s.filePrefix, date, filePerm)
+	leaves, err := s.splitLeaves(s.settings.schedule.start(at))
+	if err != nil {
+		return err
+	}
+	newFiles, err := opener(s.directory, leaves, filePerm)
 	if err != nil {
 		return err
 	}
+	newFiles = s.withBuffer(s.withSizeLimit(newFiles, leaves))
 
 	s.mutex.Lock()
 	if s.closed {
@@ -352,103 +497,185 @@ func (s *SplitOutput) openFiles() error {
 		)
 	}
 	previous := s.replaceFiles(newFiles)
+	previousLeaves := s.leaves
+	s.leaves = leaves
 	s.mutex.Unlock()
 
-	return previous.close()
+	s.reportInternalEvents(previous.failures(InternalEventClose, s.directory, previousLeaves, writeSyncCloser.Close))
+	err = s.updateCurrentLinks(leaves)
+	closed := make([]string, 0, len(previousLeaves))
+	for index, leaf := range previousLeaves {
+		if leaf != leaves[index] {
+			closed = append(closed, leaf)
+		}
+	}
+	if len(closed) > 0 {
+		s.finishRotation(closed, leaves, at)
+	}
+	return err
 }
 
-func (s *SplitOutput) rotateDaily() {
+// onSizeRolled 在持有 mutex 時由 sizeRollingFile 呼叫，只排入背景工作。
+func (s *SplitOutput) onSizeRolled(backup string) {
+	s.compressLater(backup)
+}
+
+// deferInternalEvent 在持有 mutex 時暫存事件，待釋放 mutex 後才交給 ErrorHandler。
+func (s *SplitOutput) deferInternalEvent(kind InternalEventKind, path string, err error) {
+	s.pending = append(s.pending, InternalEvent{Kind: kind, Path: path, Err: err})
+}
+
+// takeInternalEvents 取出暫存事件；呼叫端必須持有 mutex。
+func (s *SplitOutput) takeInternalEvents() []InternalEvent {
+	events := s.pending
+	s.pending = nil
+	return events
+}
+
+// reportInternalEvents 將事件交給 ErrorHandler；呼叫端不得持有 mutex。
+func (s *SplitOutput) reportInternalEvents(events []InternalEvent) {
+	for _, event := range events {
+		s.settings.reportInternalEvent(event.Kind, event.Path, event.Err)
+	}
+}
+
+func (s *SplitOutput) rotateOnSchedule() {
 	defer close(s.done)
 
+	var rotatedAt time.Time
 	for {
-		now := s.clock.Now()
-		next := now.Add(24 * time.Hour)
-		next = time.Date(next.Year(), next.Month(), next.Day(), 0, 0, 0, 0, next.Location())
-		timer := s.clock.NewTimer(next.Sub(now))
+		// 時鐘略早於邊界觸發時以邊界為準，避免同一邊界重複換檔。
+		now := s.now()
+		if now.Before(rotatedAt) {
+			now = rotatedAt
+		}
+		next := s.settings.schedule.next(now)
+		timer := s.clock.NewTimer(next.Sub(s.now()))
 
 		select {
 		case <-s.stop:
 			timer.Stop()
 			return
 		case <-timer.C():
-			if err := s.openFiles(); err != nil {
+			rotatedAt = s.now()
+			if rotatedAt.Before(next) {
+				rotatedAt = next
+			}
+			if err := s.openFilesAt(rotatedAt); err != nil {
 				if errors.Is(err, os.ErrClosed) {
 					return
 				}
-				fmt.Fprintf(os.Stderr, "每日換檔失敗：%v\n", err)
+				s.settings.reportInternalEvent(InternalEventRotation, s.directory, err)
+				continue
 			}
+			s.settings.reportInternalEvent(InternalEventRetention, s.directory, s.pruneLogFiles())
 		}
 	}
 }
 
 // Write 依日誌級別寫入對應檔案。
+//
+// 寫入失敗與依大小換檔失敗會在釋放 mutex 後交給 ErrorHandler。啟用 WithDiskSpaceGuard
+// 且可用空間不足時，低於保留級別的資料視為已寫入並丟棄。
 func (s *SplitOutput) Write(level zapcore.Level, data []byte) (int, error) {
+	if !s.diskGuard.allow(level) {
+		return len(data), nil
+	}
+	return s.write(level, data)
+}
+
+// write 不經過磁碟空間檢查；core 已在 Check 階段以實際級別檢查。
+func (s *SplitOutput) write(level zapcore.Level, data []byte) (int, error) {
 	s.mutex.Lock()
-	defer s.mutex.Unlock()
+	written, failed, err := s.writeLocked(level, data)
+	events := append(s.takeInternalEvents(), failed...)
+	s.mutex.Unlock()
+
+	s.reportInternalEvents(events)
+	return written, err
+}
 
+func (s *SplitOutput) writeLocked(level zapcore.Level, data []byte) (int, []InternalEvent, error) {
 	if s.closed {
-		return 0, fmt.Errorf("分級輸出已關閉：%w", os.ErrClosed)
+		return 0, nil, fmt.Errorf("分級輸出已關閉：%w", os.ErrClosed)
 	}
 
-	var output io.Writer
-	switch level {
-	case zapcore.InfoLevel:
-		output = s.infoOut
-	case zapcore.WarnLevel:
-		output = s.warnOut
-	case zapcore.ErrorLevel, zapcore.DPanicLevel, zapcore.PanicLevel, zapcore.FatalLevel:
-		output = s.errorOut
-	default:
-		output = s.infoOut
+	output, routed := s.outputFor(level)
+	if !routed {
+		return len(data), nil, nil
 	}
 	if output == nil {
-		return 0, fmt.Errorf("分級輸出尚未初始化：%w", os.ErrInvalid)
+		return 0, nil, fmt.Errorf("分級輸出尚未初始化：%w", os.ErrInvalid)
 	}
-	return output.Write(data)
+	written, err := output.Write(data)
+	if err != nil {
+		return written, []InternalEvent{{Kind: InternalEventWrite, Path: s.pathFor(level), Err: err}}, err
+	}
+	return written, nil, nil
 }
 
 // Sync 將所有分級日誌檔同步至儲存裝置。
 func (s *SplitOutput) Sync() error {
 	s.mutex.Lock()
-	defer s.mutex.Unlock()
-
 	if s.closed {
+		s.mutex.Unlock()
 		return fmt.Errorf("分級輸出已關閉：%w", os.ErrClosed)
 	}
-	return s.currentFiles().sync()
+	events := s.outputs.failures(InternalEventSync, s.directory, s.leaves, writeSyncCloser.Sync)
+	s.mutex.Unlock()
+
+	s.reportInternalEvents(events)
+	return internalEventsError(events)
 }
 
 func (s *SplitOutput) syncLevel(level zapcore.Level) error {
 	s.mutex.Lock()
-	defer s.mutex.Unlock()
-
 	if s.closed {
+		s.mutex.Unlock()
 		return fmt.Errorf("分級輸出已關閉：%w", os.ErrClosed)
 	}
 
-	var output writeSyncCloser
-	switch level {
-	case zapcore.WarnLevel:
-		output = s.warnOut
-	case zapcore.ErrorLevel, zapcore.DPanicLevel, zapcore.PanicLevel, zapcore.FatalLevel:
-		output = s.errorOut
-	default:
-		output = s.infoOut
+	output, routed := s.outputFor(level)
+	if !routed {
+		s.mutex.Unlock()
+		return nil
 	}
 	if output == nil {
+		s.mutex.Unlock()
 		return fmt.Errorf("分級輸出尚未初始化：%w", os.ErrInvalid)
 	}
-	return output.Sync()
+	err := output.Sync()
+	path := s.pathFor(level)
+	s.mutex.Unlock()
+
+	s.settings.reportInternalEvent(InternalEventSync, path, err)
+	return err
 }
 
-// Close 停止每日換檔 worker，並關閉所有分級日誌檔。
+// pathFor 回傳接收 level 的目前檔案完整路徑；呼叫端必須持有 mutex。
+func (s *SplitOutput) pathFor(level zapcore.Level) string {
+	routes := s.routes
+	if routes == nil {
+		routes = DefaultSplitRoutes()
+	}
+	index := splitRouteIndex(routes, level)
+	if index < 0 || index >= len(s.leaves) {
+		return s.directory
+	}
+	return filepath.Join(s.directory, s.leaves[index])
+}
+
+// Close 停止換檔 worker、寫出緩衝並關閉所有分級日誌檔，並等待已排入的背景壓縮與換檔 hook 完成。
 func (s *SplitOutput) Close() error {
 	s.closeOnce.Do(func() {
 		s.mutex.Lock()
 		s.closed = true
-		files := s.replaceFiles(splitFileSet{})
+		files := s.replaceFiles(nil)
+		leaves := s.leaves
 		stop := s.stop
 		done := s.done
+		watchDone := s.watchDone
+		flushDone := s.flushDone
 		if stop != nil {
 			close(stop)
 		}
@@ -457,7 +684,22 @@ func (s *SplitOutput) Close() error {
 		if done != nil {
 			<-done
 		}
-		s.closeErr = files.close()
+		if watchDone != nil {
+			<-watchDone
+		}
+		if flushDone != nil {
+			<-flushDone
+		}
+		events := files.failures(InternalEventClose, s.directory, leaves, writeSyncCloser.Close)
+		s.reportInternalEvents(events)
+		s.closeErr = internalEventsError(events)
+		// 壓縮完成後才排入 hook，因此先等待壓縮 queue 再關閉 hook queue。
+		s.compressor.close()
+		if abandoned, ok := s.hooks.closeWithin(rotationHookCloseTimeout); !ok {
+			s.settings.reportInternalEvent(InternalEventHook, "", fmt.Errorf(
+				"%w: Close 等待 hook 超過 %s，略過 %d 筆尚未執行的換檔事件",
+				ErrRotationHookDropped, rotationHookCloseTimeout, abandoned))
+		}
 	})
 	return s.closeErr
 }
@@ -468,7 +710,8 @@ type splitOutputWrapper struct {
 }
 
 func (w *splitOutputWrapper) Write(data []byte) (int, error) {
-	return w.so.Write(w.lvl, data)
+	written, err := w.so.write(w.lvl, data)
+	return written, w.so.settings.zapWriteError(err)
 }
 
 func (w *splitOutputWrapper) Sync() error {
@@ -501,13 +744,16 @@ func GetSplitCoreWithOptions(
 	}
 
 	encoder := zapcore.NewJSONEncoder(encoderConfig)
-	infoOut := zapcore.AddSync(&splitOutputWrapper{so: splitOut, lvl: zapcore.InfoLevel})
-	warnOut := zapcore.AddSync(&splitOutputWrapper{so: splitOut, lvl: zapcore.WarnLevel})
-	errorOut := zapcore.AddSync(&splitOutputWrapper{so: splitOut, lvl: zapcore.ErrorLevel})
-	core := buildSplitCore(encoder, SplitSinks{
-		Info:  infoOut,
-		Warn:  warnOut,
-		Error: errorOut,
-	})
-	return core, func() { _ = splitOut.Close() }, nil
+	return splitOut.core(encoder, nil), func() { _ = splitOut.Close() }, nil
+}
+
+// core 建立依路由表寫入本 SplitOutput 的 zap core，不取得 SplitOutput 的 Close ownership。
+//
+// level 不為 nil 時，各 route 另外只接收 level 啟用的級別。
+func (s *SplitOutput) core(encoder zapcore.Encoder, level zapcore.LevelEnabler) zapcore.Core {
+	sinks := make([]zapcore.WriteSyncer, len(s.routes))
+	for index, route := range s.routes {
+		sinks[index] = zapcore.AddSync(&splitOutputWrapper{so: s, lvl: route.MinLevel})
+	}
+	return withDiskSpaceGuard(buildRoutedCore(encoder, s.routes, sinks, level), s.diskGuard)
 }
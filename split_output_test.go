@@ -21,6 +21,7 @@ type recordingWriteSyncCloser struct {
 	syncCalls  int
 	closeErr   error
 	syncErr    error
+	writeErr   error
 }
 
 type recordingSplitSink struct {
@@ -89,9 +90,10 @@ func (t *manualRotationTimer) isStopped() bool {
 }
 
 type manualRotationClock struct {
-	mu     sync.Mutex
-	now    time.Time
-	timers chan *manualRotationTimer
+	mu        sync.Mutex
+	now       time.Time
+	durations []time.Duration
+	timers    chan *manualRotationTimer
 }
 
 func newManualRotationClock(now time.Time) *manualRotationClock {
@@ -107,12 +109,21 @@ func (c *manualRotationClock) Now() time.Time {
 	return c.now
 }
 
-func (c *manualRotationClock) NewTimer(time.Duration) rotationTimer {
+func (c *manualRotationClock) NewTimer(duration time.Duration) rotationTimer {
+	c.mu.Lock()
+	c.durations = append(c.durations, duration)
+	c.mu.Unlock()
 	timer := newManualRotationTimer()
 	c.timers <- timer
 	return timer
 }
 
+func (c *manualRotationClock) timerDurations() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]time.Duration(nil), c.durations...)
+}
+
 func (c *manualRotationClock) setNow(now time.Time) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -131,6 +142,9 @@ func (c *manualRotationClock) nextTimer(t *testing.T) *manualRotationTimer {
 }
 
 func (r *recordingWriteSyncCloser) Write(p []byte) (int, error) {
+	if r.writeErr != nil {
+		return 0, r.writeErr
+	}
 	return len(p), nil
 }
 
@@ -510,11 +524,11 @@ func TestSplitOutputCloseStopsRotation(t *testing.T) {
 	clock := newManualRotationClock(now)
 	var openerMu sync.Mutex
 	openerCalls := 0
-	opener := func(directory, filePrefix, date string) (splitFileSet, error) {
+	opener := func(directory string, leaves []string) (splitFileSet, error) {
 		openerMu.Lock()
 		openerCalls++
 		openerMu.Unlock()
-		return openSplitFiles(directory, filePrefix, date)
+		return openSplitFiles(directory, leaves)
 	}
 
 	so, err := newSplitOutput(tmpDir, "app", clock, opener)
@@ -548,9 +562,7 @@ func TestSplitOutputCloseIdempotent(t *testing.T) {
 	warnOut := &recordingWriteSyncCloser{}
 	errorOut := &recordingWriteSyncCloser{}
 	so := &SplitOutput{
-		infoOut:  infoOut,
-		warnOut:  warnOut,
-		errorOut: errorOut,
+		outputs: splitFileSet{infoOut, warnOut, errorOut},
 	}
 
 	const closeWorkers = 8
@@ -583,9 +595,11 @@ func TestSplitOutputCloseReturnsAllErrors(t *testing.T) {
 	warnErr := errors.New("warn 關閉失敗")
 	errorErr := errors.New("error 關閉失敗")
 	so := &SplitOutput{
-		infoOut:  &recordingWriteSyncCloser{closeErr: infoErr},
-		warnOut:  &recordingWriteSyncCloser{closeErr: warnErr},
-		errorOut: &recordingWriteSyncCloser{closeErr: errorErr},
+		outputs: splitFileSet{
+			&recordingWriteSyncCloser{closeErr: infoErr},
+			&recordingWriteSyncCloser{closeErr: warnErr},
+			&recordingWriteSyncCloser{closeErr: errorErr},
+		},
 	}
 
 	err := so.Close()
@@ -620,7 +634,7 @@ func TestSplitOutputRotationFailureKeepsCurrentFiles(t *testing.T) {
 	rotationAttempted := make(chan struct{})
 	var openerMu sync.Mutex
 	openerCalls := 0
-	opener := func(directory, filePrefix, date string) (splitFileSet, error) {
+	opener := func(directory string, leaves []string) (splitFileSet, error) {
 		openerMu.Lock()
 		openerCalls++
 		call := openerCalls
@@ -629,7 +643,7 @@ func TestSplitOutputRotationFailureKeepsCurrentFiles(t *testing.T) {
 			close(rotationAttempted)
 			return splitFileSet{}, errors.New("測試換檔失敗")
 		}
-		return openSplitFiles(directory, filePrefix, date)
+		return openSplitFiles(directory, leaves)
 	}
 
 	so, err := newSplitOutput(tmpDir, "app", clock, opener)
@@ -881,9 +895,7 @@ func TestSplitOutputSync(t *testing.T) {
 	warnOut := &recordingWriteSyncCloser{}
 	errorOut := &recordingWriteSyncCloser{}
 	so := &SplitOutput{
-		infoOut:  infoOut,
-		warnOut:  warnOut,
-		errorOut: errorOut,
+		outputs: splitFileSet{infoOut, warnOut, errorOut},
 	}
 
 	if err := so.Sync(); err != nil {
@@ -915,9 +927,11 @@ func TestSplitOutputSyncReturnsAllErrors(t *testing.T) {
 	warnErr := errors.New("warn 同步失敗")
 	errorErr := errors.New("error 同步失敗")
 	so := &SplitOutput{
-		infoOut:  &recordingWriteSyncCloser{syncErr: infoErr},
-		warnOut:  &recordingWriteSyncCloser{syncErr: warnErr},
-		errorOut: &recordingWriteSyncCloser{syncErr: errorErr},
+		outputs: splitFileSet{
+			&recordingWriteSyncCloser{syncErr: infoErr},
+			&recordingWriteSyncCloser{syncErr: warnErr},
+			&recordingWriteSyncCloser{syncErr: errorErr},
+		},
 	}
 
 	err := so.Sync()
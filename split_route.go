@@ -0,0 +1,105 @@
+package zlogger
+
+import (
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// ErrInvalidSplitRoute 表示分級路由表設定無效。
+var ErrInvalidSplitRoute = errors.New("分級路由設定無效")
+
+// SplitRoute 描述 SplitOutput 的一個分級檔案。
+//
+// Name 會代入檔名樣板的 {level}，MinLevel 至 MaxLevel（皆含）為此檔案接收的級別。
+type SplitRoute struct {
+	Name     string
+	MinLevel zapcore.Level
+	MaxLevel zapcore.Level
+}
+
+// DefaultSplitRoutes 回傳預設的三路分級：DEBUG 與 INFO 寫入 info，WARN 寫入 warn，
+// ERROR 以上寫入 error。
+func DefaultSplitRoutes() []SplitRoute {
+	return []SplitRoute{
+		{Name: "info", MinLevel: zapcore.DebugLevel, MaxLevel: zapcore.InfoLevel},
+		{Name: "warn", MinLevel: zapcore.WarnLevel, MaxLevel: zapcore.WarnLevel},
+		{Name: "error", MinLevel: zapcore.ErrorLevel, MaxLevel: zapcore.FatalLevel},
+	}
+}
+
+// WithSplitRoutes 以路由表取代 SplitOutput 預設的 info／warn／error 三個檔案。
+//
+// 每個 route 的 Name 必須是唯一且安全的 leaf name 片段，級別範圍必須位於
+// DEBUG 至 FATAL 之間且不得互相重疊。未被任何 route 涵蓋的級別不寫入檔案。
+// 多於一個 route 時，檔名樣板必須包含 {level}。
+func WithSplitRoutes(routes ...SplitRoute) FileOutputOption {
+	return fileOutputOptionFunc(func(settings *fileOutputSettings) error {
+		if err := validateSplitRoutes(routes); err != nil {
+			return err
+		}
+		settings.routes = append([]SplitRoute(nil), routes...)
+		return nil
+	})
+}
+
+func validateSplitRoutes(routes []SplitRoute) error {
+	if len(routes) == 0 {
+		return fmt.Errorf("%w: 至少需要一個 route", ErrInvalidSplitRoute)
+	}
+	names := make(map[string]struct{}, len(routes))
+	for index, route := range routes {
+		if err := validateLogLeaf(route.Name, false); err != nil {
+			return fmt.Errorf("%w: 第 %d 個 route 名稱: %w", ErrInvalidSplitRoute, index+1, err)
+		}
+		if _, ok := names[route.Name]; ok {
+			return fmt.Errorf("%w: route 名稱 %q 重複", ErrInvalidSplitRoute, route.Name)
+		}
+		names[route.Name] = struct{}{}
+
+		if route.MinLevel < zapcore.DebugLevel || route.MaxLevel > zapcore.FatalLevel ||
+			route.MinLevel > route.MaxLevel {
+			return fmt.Errorf(
+				"%w: route %q 的級別範圍 %s～%s 無效",
+				ErrInvalidSplitRoute,
+				route.Name,
+				route.MinLevel,
+				route.MaxLevel,
+			)
+		}
+		for _, previous := range routes[:index] {
+			if route.MinLevel <= previous.MaxLevel && previous.MinLevel <= route.MaxLevel {
+				return fmt.Errorf(
+					"%w: route %q 與 %q 的級別範圍重疊",
+					ErrInvalidSplitRoute,
+					route.Name,
+					previous.Name,
+				)
+			}
+		}
+	}
+	return nil
+}
+
+func (r SplitRoute) enabled(level zapcore.Level) bool {
+	return level >= r.MinLevel && level <= r.MaxLevel
+}
+
+// splitRouteIndex 回傳接收 level 的 route 位置；沒有 route 接收時回傳 -1。
+func splitRouteIndex(routes []SplitRoute, level zapcore.Level) int {
+	for index, route := range routes {
+		if route.enabled(level) {
+			return index
+		}
+	}
+	return -1
+}
+
+func splitRouteNames(routes []SplitRoute) []string {
+	names := make([]string, len(routes))
+	for index, route := range routes {
+		names[index] = route.Name
+	}
+	return names
+}
@@ -0,0 +1,162 @@
+package zlogger
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestWithSplitRoutesRejectsInvalidRoutes(t *testing.T) {
+	tests := []struct {
+		name   string
+		routes []SplitRoute
+	}{
+		{name: "空路由表"},
+		{name: "空名稱", routes: []SplitRoute{{MinLevel: zapcore.InfoLevel, MaxLevel: zapcore.InfoLevel}}},
+		{
+			name:   "名稱含路徑",
+			routes: []SplitRoute{{Name: "a/b", MinLevel: zapcore.InfoLevel, MaxLevel: zapcore.InfoLevel}},
+		},
+		{
+			name: "名稱重複",
+			routes: []SplitRoute{
+				{Name: "app", MinLevel: zapcore.DebugLevel, MaxLevel: zapcore.InfoLevel},
+				{Name: "app", MinLevel: zapcore.WarnLevel, MaxLevel: zapcore.FatalLevel},
+			},
+		},
+		{
+			name:   "範圍顛倒",
+			routes: []SplitRoute{{Name: "app", MinLevel: zapcore.ErrorLevel, MaxLevel: zapcore.InfoLevel}},
+		},
+		{
+			name:   "超出級別範圍",
+			routes: []SplitRoute{{Name: "app", MinLevel: zapcore.DebugLevel - 1, MaxLevel: zapcore.InfoLevel}},
+		},
+		{
+			name: "範圍重疊",
+			routes: []SplitRoute{
+				{Name: "low", MinLevel: zapcore.DebugLevel, MaxLevel: zapcore.WarnLevel},
+				{Name: "high", MinLevel: zapcore.WarnLevel, MaxLevel: zapcore.FatalLevel},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := filepath.Join(t.TempDir(), "不應建立")
+			output, err := NewSplitOutputWithOptions(base, "app", WithSplitRoutes(tt.routes...))
+			if output != nil {
+				_ = output.Close()
+				t.Fatal("無效路由表不應回傳 SplitOutput")
+			}
+			if !errors.Is(err, ErrInvalidSplitRoute) {
+				t.Fatalf("錯誤 = %v，預期 ErrInvalidSplitRoute", err)
+			}
+			assertPathDoesNotExist(t, base)
+		})
+	}
+}
+
+func TestWithSplitRoutesRequiresLevelTokenForMultipleRoutes(t *testing.T) {
+	base := filepath.Join(t.TempDir(), "不應建立")
+	output, err := NewSplitOutputWithOptions(
+		base,
+		"app",
+		WithSplitRoutes(
+			SplitRoute{Name: "low", MinLevel: zapcore.DebugLevel, MaxLevel: zapcore.InfoLevel},
+			SplitRoute{Name: "high", MinLevel: zapcore.WarnLevel, MaxLevel: zapcore.FatalLevel},
+		),
+		WithFileNameTemplate("{prefix}-{date}.log"),
+	)
+	if output != nil {
+		_ = output.Close()
+		t.Fatal("多個 route 共用同一檔名時不應回傳 SplitOutput")
+	}
+	if !errors.Is(err, ErrInvalidFileNameTemplate) {
+		t.Fatalf("錯誤 = %v，預期 ErrInvalidFileNameTemplate", err)
+	}
+}
+
+func TestSplitOutputCustomRoutes(t *testing.T) {
+	base := t.TempDir()
+	now := time.Date(2026, time.July, 29, 10, 0, 0, 0, time.Local)
+	clock := newManualRotationClock(now)
+	settings, err := resolveFileOutputOptions(WithSplitRoutes(
+		SplitRoute{Name: "debug", MinLevel: zapcore.DebugLevel, MaxLevel: zapcore.DebugLevel},
+		SplitRoute{Name: "info", MinLevel: zapcore.InfoLevel, MaxLevel: zapcore.InfoLevel},
+		SplitRoute{Name: "warn", MinLevel: zapcore.WarnLevel, MaxLevel: zapcore.WarnLevel},
+		SplitRoute{Name: "error", MinLevel: zapcore.ErrorLevel, MaxLevel: zapcore.DPanicLevel},
+		SplitRoute{Name: "fatal", MinLevel: zapcore.PanicLevel, MaxLevel: zapcore.FatalLevel},
+	))
+	if err != nil {
+		t.Fatalf("解析路由表失敗：%v", err)
+	}
+	output, err := newSplitOutputWithSettings(base, "app", clock, openSplitFilesWithPermissions, settings)
+	if err != nil {
+		t.Fatalf("建立 SplitOutput 失敗：%v", err)
+	}
+	t.Cleanup(func() { _ = output.Close() })
+
+	timer := clock.nextTimer(t)
+	nextDay := now.AddDate(0, 0, 1)
+	clock.setNow(nextDay)
+	timer.fire(nextDay)
+	clock.nextTimer(t)
+
+	logger := zap.New(output.core(zapcore.NewConsoleEncoder(zapcore.EncoderConfig{MessageKey: "msg"}), nil))
+	logger.Debug("d")
+	logger.Info("i")
+	logger.Warn("w")
+	logger.Error("e")
+	if _, err := output.Write(zapcore.PanicLevel, []byte("p\n")); err != nil {
+		t.Fatalf("寫入 panic 失敗：%v", err)
+	}
+	if err := output.Sync(); err != nil {
+		t.Fatalf("同步 SplitOutput 失敗：%v", err)
+	}
+
+	date := now.Format(dailyRotationLayout)
+	nextDate := nextDay.Format(dailyRotationLayout)
+	want := make([]string, 0, 10)
+	for _, name := range []string{"debug", "info", "warn", "error", "fatal"} {
+		want = append(want, "app-"+name+"-"+date+".log", "app-"+name+"-"+nextDate+".log")
+	}
+	assertRetainedLogFiles(t, base, want...)
+	for name, content := range map[string]string{
+		"debug": "d\n",
+		"info":  "i\n",
+		"warn":  "w\n",
+		"error": "e\n",
+		"fatal": "p\n",
+	} {
+		assertFileContent(t, filepath.Join(base, "app-"+name+"-"+nextDate+".log"), content)
+	}
+}
+
+func TestSplitOutputSingleRouteDropsUncoveredLevels(t *testing.T) {
+	base := t.TempDir()
+	output := newRetentionTestOutput(t, base, time.Date(2026, time.July, 29, 10, 0, 0, 0, time.Local),
+		WithSplitRoutes(SplitRoute{Name: "problems", MinLevel: zapcore.WarnLevel, MaxLevel: zapcore.FatalLevel}),
+		WithFileNameTemplate("{prefix}-{date}.log"),
+	)
+	t.Cleanup(func() { _ = output.Close() })
+
+	for level, message := range map[zapcore.Level]string{
+		zapcore.InfoLevel:  "info\n",
+		zapcore.ErrorLevel: "error\n",
+	} {
+		written, err := output.Write(level, []byte(message))
+		if err != nil || written != len(message) {
+			t.Fatalf("寫入 %s = %d, %v，預期 %d, nil", level, written, err, len(message))
+		}
+	}
+	if err := output.Sync(); err != nil {
+		t.Fatalf("同步 SplitOutput 失敗：%v", err)
+	}
+	assertRetainedLogFiles(t, base, "app-2026-07-29.log")
+	assertFileContent(t, filepath.Join(base, "app-2026-07-29.log"), "error\n")
+}